@@ -5,6 +5,12 @@ const (
 	CmdThemesList   = "cmd_themes_list"
 	CmdThemesGet    = "cmd_themes_get"
 	CmdThemesDelete = "cmd_themes_delete"
+	CmdServe        = "cmd_serve"
+	CmdMock         = "cmd_mock"
+	CmdVerify       = "cmd_verify"
+	CmdRecord       = "cmd_record"
+	CmdTest         = "cmd_test"
+	CmdSunset       = "cmd_sunset"
 	CmdUnknown      = "cmd_unknown"
 )
 
@@ -10,7 +10,6 @@ import (
 	"github.com/aubm/postmanerator/postman"
 	"github.com/aubm/postmanerator/themes"
 	"github.com/fatih/color"
-	"github.com/howeyc/fsnotify"
 )
 
 type Default struct {
@@ -18,6 +17,7 @@ type Default struct {
 	Themes interface {
 		Open(themeName string) (*themes.Theme, error)
 		Download(themeName string) error
+		DownloadParents(themeName string) error
 	} `inject:""`
 	CollectionBuilder interface {
 		FromFile(file string, options postman.BuilderOptions) (postman.Collection, error)
@@ -27,6 +27,7 @@ type Default struct {
 	} `inject:""`
 	Renderer interface {
 		Render(w io.Writer, theme *themes.Theme, collection postman.Collection) error
+		RenderPages(out themes.Output, theme *themes.Theme, collection postman.Collection) error
 	} `inject:""`
 }
 
@@ -54,14 +55,10 @@ func (c *Default) Do() error {
 		return err
 	}
 
-	writeOutput := func() {
-		c.writeOutput(theme, postmanCollection)
-	}
-
-	writeOutput()
+	c.writeOutput(theme, postmanCollection)
 
 	if c.Config.Watch {
-		return c.watchThemeFilesChanges(theme, writeOutput)
+		return c.watchChanges(theme)
 	}
 
 	return nil
@@ -71,57 +68,30 @@ func (c *Default) validateUserInput() error {
 	if c.Config.CollectionFile == "" {
 		return errors.New("You must provide a collection using the -collection flag")
 	}
+	if c.Config.OutputFile != "" && c.Config.OutputDirectory != "" {
+		return errors.New("The -output and -output-dir flags can not be used together")
+	}
 	return nil
 }
 
-func (c *Default) getPostmanEnvironment() (environment postman.Environment, err error) {
-	if c.Config.EnvironmentFile == "" {
-		return
-	}
-
-	environment, err = c.EnvironmentBuilder.FromFile(c.Config.EnvironmentFile)
-	if err != nil {
-		err = fmt.Errorf("Failed to parse environment file: %v", err)
-	}
-
-	return
+func (c *Default) getPostmanEnvironment() (postman.Environment, error) {
+	return buildEnvironment(c.Config, c.EnvironmentBuilder)
 }
 
 func (c *Default) getPostmanCollection(environment postman.Environment) (postman.Collection, error) {
-	options := postman.BuilderOptions{
-		IgnoredRequestHeaders:  c.Config.IgnoredRequestHeaders.Values,
-		IgnoredResponseHeaders: c.Config.IgnoredResponseHeaders.Values,
-		EnvironmentVariables:   environment,
-	}
-	postmanCollection, err := c.CollectionBuilder.FromFile(c.Config.CollectionFile, options)
-	if err != nil {
-		return postman.Collection{}, fmt.Errorf("Failed to parse collection file: %v", err)
-	}
-
-	return postmanCollection, nil
+	return buildCollection(c.Config, c.CollectionBuilder, environment, true)
 }
 
 func (c *Default) getTheme() (*themes.Theme, error) {
-	usedTheme := c.Config.UsedTheme
-
-	theme, err := c.Themes.Open(usedTheme)
-	if err == nil {
-		return theme, nil
-	}
-
-	if err != themes.ErrThemeNotFound {
-		return nil, fmt.Errorf("Failed to open the theme: %v", err)
-	}
-
-	fmt.Fprintln(c.Config.Out, color.BlueString("Theme '%v' not found, trying to download it...", usedTheme))
-	if err := c.Themes.Download(usedTheme); err != nil {
-		return nil, err
-	}
-
-	return c.Themes.Open(usedTheme)
+	return openTheme(c.Config, c.Themes)
 }
 
 func (c *Default) writeOutput(theme *themes.Theme, collection postman.Collection) {
+	if c.Config.OutputDirectory != "" {
+		c.writeOutputDirectory(theme, collection)
+		return
+	}
+
 	outputFile, err := c.createOutputWriter()
 	if err != nil {
 		fmt.Fprintln(c.Config.Out, color.RedString(err.Error()))
@@ -137,6 +107,16 @@ func (c *Default) writeOutput(theme *themes.Theme, collection postman.Collection
 	fmt.Fprintln(c.Config.Out, color.GreenString("SUCCESS."))
 }
 
+func (c *Default) writeOutputDirectory(theme *themes.Theme, collection postman.Collection) {
+	fmt.Fprint(c.Config.Out, "Generating output... ")
+	out := themes.DirectoryOutput{Path: c.Config.OutputDirectory}
+	if err := c.Renderer.RenderPages(out, theme, collection); err != nil {
+		fmt.Fprintln(c.Config.Out, color.RedString("FAIL. %v", err))
+		return
+	}
+	fmt.Fprintln(c.Config.Out, color.GreenString("SUCCESS."))
+}
+
 func (c *Default) createOutputWriter() (io.WriteCloser, error) {
 	if c.Config.OutputFile == "" {
 		return nopCloser{c.Config.Out}, nil
@@ -149,35 +129,47 @@ func (c *Default) createOutputWriter() (io.WriteCloser, error) {
 	return out, nil
 }
 
-func (c *Default) watchThemeFilesChanges(theme *themes.Theme, action func()) error {
-	watcher, err := fsnotify.NewWatcher()
+func (c *Default) watchChanges(theme *themes.Theme) error {
+	watcher, err := newFileWatcher()
 	if err != nil {
-		return fmt.Errorf("Failed to create file watcher: %v", err)
+		return err
 	}
 	defer watcher.Close()
 
-	go c.executeActionForEachWatcherEvent(watcher, action)
-
-	if err := watcher.Watch(theme.Path); err != nil {
-		return fmt.Errorf("Failed to watch theme directory: %v", err)
+	if err := watchSources(watcher, c.Config, theme); err != nil {
+		return err
 	}
 
+	go watcher.Run(c.regenerate, func(err error) {
+		fmt.Fprintln(c.Config.Out, color.RedString("FAIL. %v", err))
+	})
+
 	c.sleep()
 
 	return nil
 }
 
-func (c *Default) executeActionForEachWatcherEvent(watcher *fsnotify.Watcher, action func()) {
-	for {
-		select {
-		case ev := <-watcher.Event:
-			if !ev.IsAttrib() {
-				action()
-			}
-		case err := <-watcher.Error:
-			fmt.Fprintln(c.Config.Out, color.RedString("FAIL. %v", err))
-		}
+// regenerate builds everything again, so that changes in the collection or the environment are taken into account.
+func (c *Default) regenerate() {
+	postmanEnvironment, err := c.getPostmanEnvironment()
+	if err != nil {
+		fmt.Fprintln(c.Config.Out, color.RedString(err.Error()))
+		return
+	}
+
+	postmanCollection, err := c.getPostmanCollection(postmanEnvironment)
+	if err != nil {
+		fmt.Fprintln(c.Config.Out, color.RedString(err.Error()))
+		return
+	}
+
+	theme, err := c.Themes.Open(c.Config.UsedTheme)
+	if err != nil {
+		fmt.Fprintln(c.Config.Out, color.RedString("Failed to open the theme: %v", err))
+		return
 	}
+
+	c.writeOutput(theme, postmanCollection)
 }
 
 func (c *Default) sleep() {
@@ -112,7 +112,7 @@ var _ = Describe("Default", func() {
 				Expect(len(mockCollectionBuilder.Calls)).To(Equal(1))
 				args := mockCollectionBuilder.Calls[0].Arguments
 				Expect(args.String(0)).To(Equal("awesome-collection.json"))
-				Expect(args.Get(1)).To(Equal(postman.BuilderOptions{}))
+				Expect(args.Get(1)).To(Equal(postman.BuilderOptions{Documentation: true}))
 			})
 
 			It("should open the right theme", func() {
@@ -141,18 +141,168 @@ var _ = Describe("Default", func() {
 				BeforeEach(func() {
 					defaultCommand.Config.IgnoredRequestHeaders = configuration.StringsFlag{Values: []string{"X-Foo", "X-Bar"}}
 					defaultCommand.Config.IgnoredResponseHeaders = configuration.StringsFlag{Values: []string{"X-Fizz", "X-Buzz"}}
+					defaultCommand.Config.AllowedRequestHeaders = configuration.StringsFlag{Values: []string{"Content-*"}}
+					defaultCommand.Config.AllowedResponseHeaders = configuration.StringsFlag{Values: []string{"/^x-rate-limit-/"}}
 				})
 
 				It("should propagate the options to the collection builder", func() {
 					args := mockCollectionBuilder.Calls[0].Arguments
 					Expect(args.Get(1)).To(Equal(postman.BuilderOptions{
+						Documentation:          true,
 						IgnoredRequestHeaders:  []string{"X-Foo", "X-Bar"},
 						IgnoredResponseHeaders: []string{"X-Fizz", "X-Buzz"},
+						AllowedRequestHeaders:  []string{"Content-*"},
+						AllowedResponseHeaders: []string{"/^x-rate-limit-/"},
 					}))
 				})
 
 			})
 
+			Context("and the strict mode", func() {
+
+				BeforeEach(func() {
+					defaultCommand.Config.Strict = true
+				})
+
+				It("should propagate the option to the collection builder", func() {
+					args := mockCollectionBuilder.Calls[0].Arguments
+					Expect(args.Get(1)).To(Equal(postman.BuilderOptions{Documentation: true, Strict: true}))
+				})
+
+			})
+
+			Context("and a structures file", func() {
+
+				BeforeEach(func() {
+					defaultCommand.Config.StructuresFile = "structures.yml"
+				})
+
+				It("should propagate the option to the collection builder", func() {
+					args := mockCollectionBuilder.Calls[0].Arguments
+					Expect(args.Get(1)).To(Equal(postman.BuilderOptions{Documentation: true, StructuresFile: "structures.yml"}))
+				})
+
+			})
+
+			Context("and the structures inference", func() {
+
+				BeforeEach(func() {
+					defaultCommand.Config.InferStructures = true
+				})
+
+				It("should propagate the option to the collection builder", func() {
+					args := mockCollectionBuilder.Calls[0].Arguments
+					Expect(args.Get(1)).To(Equal(postman.BuilderOptions{Documentation: true, InferStructures: true}))
+				})
+
+			})
+
+			Context("and filters", func() {
+
+				BeforeEach(func() {
+					defaultCommand.Config.Include.Set("Public/**,Partners/**")
+					defaultCommand.Config.Exclude.Set("*/Debug *")
+					defaultCommand.Config.Tags.Set("public,!internal")
+				})
+
+				It("should propagate the patterns to the collection builder", func() {
+					args := mockCollectionBuilder.Calls[0].Arguments
+					Expect(args.Get(1)).To(Equal(postman.BuilderOptions{
+						Documentation: true,
+						Include:       []string{"Public/**", "Partners/**"},
+						Exclude:       []string{"*/Debug *"},
+						Tags:          []string{"public", "!internal"},
+					}))
+				})
+
+			})
+
+			Context("and a sort order", func() {
+
+				BeforeEach(func() {
+					defaultCommand.Config.Sort = "method"
+				})
+
+				It("should propagate the option to the collection builder", func() {
+					args := mockCollectionBuilder.Calls[0].Arguments
+					Expect(args.Get(1)).To(Equal(postman.BuilderOptions{Documentation: true, Sort: "method"}))
+				})
+
+			})
+
+			Context("and redaction rules", func() {
+
+				BeforeEach(func() {
+					defaultCommand.Config.Redact = true
+					defaultCommand.Config.RedactPatterns.Set(`sk_live_\w{8,}`)
+					defaultCommand.Config.RedactPatterns.Set(`card=(\d{12,19})`)
+				})
+
+				It("should propagate the options to the collection builder", func() {
+					args := mockCollectionBuilder.Calls[0].Arguments
+					Expect(args.Get(1)).To(Equal(postman.BuilderOptions{
+						Documentation:  true,
+						Redact:         true,
+						RedactPatterns: []string{`sk_live_\w{8,}`, `card=(\d{12,19})`},
+					}))
+				})
+
+			})
+
+			Context("and the collection has redacted secrets", func() {
+
+				var mockStdErr *bytes.Buffer
+
+				BeforeEach(func() {
+					mockStdErr = new(bytes.Buffer)
+					defaultCommand.Config.Err = mockStdErr
+					collection.Redactions = []postman.Redaction{{Location: "Get all cats, request header Authorization", Rule: "sensitive header"}}
+					mockCollectionBuilder.ExpectedCalls = nil
+					mockCollectionBuilder.On("FromFile", any, any).Return(collection, nil)
+				})
+
+				It("should report them on the error output", func() {
+					Expect(mockStdErr.String()).To(Equal("Redacted Get all cats, request header Authorization (sensitive header)\n"))
+				})
+
+			})
+
+			Context("and body limits", func() {
+
+				BeforeEach(func() {
+					defaultCommand.Config.MaxBodySize = 4096
+					defaultCommand.Config.MaxArrayItems = 3
+				})
+
+				It("should propagate the options to the collection builder", func() {
+					args := mockCollectionBuilder.Calls[0].Arguments
+					Expect(args.Get(1)).To(Equal(postman.BuilderOptions{Documentation: true, MaxBodySize: 4096, MaxArrayItems: 3}))
+				})
+
+			})
+
+			Context("and the collection has warnings", func() {
+
+				var mockStdErr *bytes.Buffer
+
+				BeforeEach(func() {
+					mockStdErr = new(bytes.Buffer)
+					defaultCommand.Config.Err = mockStdErr
+					collection.Warnings = []string{"Get all cats, line 2: ReferenceError: 'foo' is not defined"}
+					mockCollectionBuilder.ExpectedCalls = nil
+					mockCollectionBuilder.On("FromFile", any, any).Return(collection, nil)
+				})
+
+				It("should print them on the error output", func() {
+					Expect(mockStdErr.String()).To(Equal(color.YellowString("Warning: Get all cats, line 2: ReferenceError: 'foo' is not defined") + "\n"))
+				})
+
+				It("should keep them out of the standard output", func() {
+					Expect(mockStdOut.String()).To(Equal("Generating output... " + color.GreenString("SUCCESS.") + "\n"))
+				})
+
+			})
+
 			Context("and using a custom environment", func() {
 
 				var environment postman.Environment
@@ -171,6 +321,7 @@ var _ = Describe("Default", func() {
 				It("should propagate the environment to the collection builder", func() {
 					args := mockCollectionBuilder.Calls[0].Arguments
 					Expect(args.Get(1)).To(Equal(postman.BuilderOptions{
+						Documentation:        true,
 						EnvironmentVariables: environment,
 					}))
 				})
@@ -191,6 +342,71 @@ var _ = Describe("Default", func() {
 
 		})
 
+		Context("when an output directory is specified", func() {
+
+			var (
+				collection postman.Collection
+				theme      *themes.Theme
+			)
+
+			BeforeEach(func() {
+				defaultCommand.Config.OutputFile = ""
+				defaultCommand.Config.OutputDirectory = "/tmp/awesome-doc"
+				collection = postman.Collection{Name: "foo"}
+				theme = &themes.Theme{Name: "foo"}
+				mockCollectionBuilder.On("FromFile", any, any).Return(collection, nil)
+				mockThemeManager.On("Open", any).Return(theme, nil)
+				mockThemeRenderer.On("RenderPages", any, any, any).Return(nil)
+			})
+
+			It("should not return an error", func() {
+				Expect(returnedError).To(BeNil())
+			})
+
+			It("should render the pages in the right directory", func() {
+				Expect(len(mockThemeRenderer.Calls)).To(Equal(1))
+				args := mockThemeRenderer.Calls[0].Arguments
+				Expect(args.Get(0)).To(Equal(themes.DirectoryOutput{Path: "/tmp/awesome-doc"}))
+				Expect(args.Get(1)).To(Equal(theme))
+				Expect(args.Get(2)).To(Equal(collection))
+			})
+
+			It("should produce the right command output", func() {
+				Expect(mockStdOut.String()).To(Equal("Generating output... " + color.GreenString("SUCCESS.") + "\n"))
+			})
+
+			Context("and an output file is specified too", func() {
+
+				BeforeEach(func() {
+					defaultCommand.Config.OutputFile = outputFilePath
+				})
+
+				It("should return an error", func() {
+					Expect(returnedError).NotTo(BeNil())
+					Expect(returnedError.Error()).To(Equal("The -output and -output-dir flags can not be used together"))
+				})
+
+				It("should not render anything", func() {
+					Expect(len(mockThemeRenderer.Calls)).To(Equal(0))
+				})
+
+			})
+
+			Context("and rendering fails", func() {
+
+				BeforeEach(func() {
+					mockThemeRenderer.ExpectedCalls = nil
+					mockThemeRenderer.On("RenderPages", any, any, any).Return(someBadError)
+				})
+
+				It("should produce the right command output", func() {
+					Expect(mockStdOut.String()).To(Equal("Generating output... " + color.RedString("FAIL. something bad happened!") + "\n"))
+				})
+
+			})
+
+		})
+
 		Context("when no collection is provided", func() {
 
 			BeforeEach(func() {
@@ -301,6 +517,37 @@ var _ = Describe("Default", func() {
 
 		})
 
+		Context("when a parent of the theme is not installed", func() {
+
+			var theme *themes.Theme
+
+			BeforeEach(func() {
+				defaultCommand.Config.UsedTheme = "custom_theme"
+				theme = &themes.Theme{Name: "custom_theme"}
+				mockCollectionBuilder.On("FromFile", any, any).Return(postman.Collection{Name: "foo"}, nil)
+				mockThemeManager.On("Open", "custom_theme").Return(&themes.Theme{}, &themes.MissingParentError{Theme: "custom_theme", Parent: "default"}).Once()
+				mockThemeManager.On("DownloadParents", "custom_theme").Return(nil).Once()
+				mockThemeManager.On("Open", "custom_theme").Return(theme, nil).Once()
+				mockThemeRenderer.On("Render", any, any, any).Return(nil)
+			})
+
+			It("should not return an error", func() {
+				Expect(returnedError).To(BeNil())
+			})
+
+			It("should download the parents and open the theme again", func() {
+				Expect(len(mockThemeManager.Calls)).To(Equal(3))
+				Expect(mockThemeManager.Calls[1].Method).To(Equal("DownloadParents"))
+				Expect(mockThemeManager.Calls[1].Arguments.String(0)).To(Equal("custom_theme"))
+				Expect(mockThemeRenderer.Calls[0].Arguments.Get(1)).To(Equal(theme))
+			})
+
+			It("should produce the right command output", func() {
+				Expect(mockStdOut.String()).To(HavePrefix(color.BlueString("Theme 'custom_theme' extends 'default', which is not installed, trying to download it...") + "\n"))
+			})
+
+		})
+
 		Context("when the theme does not exist", func() {
 
 			var collection postman.Collection
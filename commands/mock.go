@@ -0,0 +1,71 @@
+package commands
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/aubm/postmanerator/configuration"
+	"github.com/aubm/postmanerator/mockserver"
+	"github.com/aubm/postmanerator/postman"
+	"github.com/fatih/color"
+)
+
+const defaultMockPort = 3000
+
+type Mock struct {
+	Config            *configuration.Configuration `inject:""`
+	CollectionBuilder interface {
+		FromFile(file string, options postman.BuilderOptions) (postman.Collection, error)
+	} `inject:""`
+	EnvironmentBuilder interface {
+		FromFile(file string) (postman.Environment, error)
+	} `inject:""`
+	ListenAndServe func(addr string, handler http.Handler) error
+}
+
+func (c *Mock) Is(name string) bool {
+	return name == CmdMock
+}
+
+func (c *Mock) Do() error {
+	if c.Config.CollectionFile == "" {
+		return errors.New("You must provide a collection using the -collection flag")
+	}
+
+	environment, err := buildEnvironment(c.Config, c.EnvironmentBuilder)
+	if err != nil {
+		return err
+	}
+
+	collection, err := buildCollection(c.Config, c.CollectionBuilder, environment, false)
+	if err != nil {
+		return err
+	}
+
+	server := mockserver.NewServer(collection)
+	server.OnResponse = c.logResponse
+
+	port := c.Config.Port
+	if port == 0 {
+		port = defaultMockPort
+	}
+	fmt.Fprintln(c.Config.Out, color.GreenString("Mock server listening on http://localhost:%d", port))
+
+	listenAndServe := c.ListenAndServe
+	if listenAndServe == nil {
+		listenAndServe = http.ListenAndServe
+	}
+	return listenAndServe(fmt.Sprintf(":%d", port), server)
+}
+
+func (c *Mock) logResponse(r *http.Request, request *postman.Request, response *postman.Response) {
+	switch {
+	case request == nil:
+		fmt.Fprintln(c.Config.Out, color.RedString("%v %v -> no matching request", r.Method, r.URL.Path))
+	case response == nil:
+		fmt.Fprintln(c.Config.Out, color.RedString("%v %v -> %v, no matching response", r.Method, r.URL.Path, request.Name))
+	default:
+		fmt.Fprintf(c.Config.Out, "%v %v -> %v, %v (%v)\n", r.Method, r.URL.Path, request.Name, response.Name, response.StatusCode)
+	}
+}
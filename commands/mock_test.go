@@ -0,0 +1,151 @@
+package commands_test
+
+import (
+	"bytes"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+
+	. "github.com/aubm/postmanerator/commands"
+	"github.com/aubm/postmanerator/configuration"
+	"github.com/aubm/postmanerator/postman"
+	. "github.com/aubm/postmanerator/postman/mocks"
+	"github.com/fatih/color"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Mock", func() {
+
+	var (
+		mockStdOut            *bytes.Buffer
+		mockCollectionBuilder *MockCollectionBuilder
+		listenedAddr          string
+		handler               http.Handler
+		mockCommand           *Mock
+	)
+
+	BeforeEach(func() {
+		mockStdOut = new(bytes.Buffer)
+		mockCollectionBuilder = &MockCollectionBuilder{}
+		listenedAddr = ""
+		handler = nil
+		mockCommand = &Mock{
+			Config: &configuration.Configuration{
+				Out:            mockStdOut,
+				CollectionFile: "awesome-collection.json",
+			},
+			CollectionBuilder:  mockCollectionBuilder,
+			EnvironmentBuilder: &MockEnvironmentBuilder{},
+			ListenAndServe: func(addr string, h http.Handler) error {
+				listenedAddr = addr
+				handler = h
+				return nil
+			},
+		}
+	})
+
+	Describe("Is", func() {
+
+		It("should be OK", func() {
+			Expect(mockCommand.Is("cmd_mock")).To(BeTrue())
+		})
+
+		It("should be KO", func() {
+			Expect(mockCommand.Is("cmd_serve")).To(BeFalse())
+		})
+
+	})
+
+	Describe("Do", func() {
+
+		var returnedError error
+
+		JustBeforeEach(func() {
+			returnedError = mockCommand.Do()
+		})
+
+		Context("when everything is ok", func() {
+
+			BeforeEach(func() {
+				mockCollectionBuilder.On("FromFile", any, any).Return(postman.Collection{
+					Requests: []postman.Request{{
+						Name:      "Get all cats",
+						Method:    "GET",
+						URL:       "{{url}}/cats",
+						Responses: []postman.Response{{Name: "OK", StatusCode: 200, Body: "[]"}},
+					}},
+				}, nil)
+			})
+
+			It("should not return an error", func() {
+				Expect(returnedError).To(BeNil())
+			})
+
+			It("should listen on the default port", func() {
+				Expect(listenedAddr).To(Equal(":3000"))
+			})
+
+			It("should serve the saved responses and log them", func() {
+				recorder := httptest.NewRecorder()
+				handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/cats", nil))
+				Expect(recorder.Code).To(Equal(200))
+				Expect(recorder.Body.String()).To(Equal("[]"))
+				Expect(mockStdOut.String()).To(HaveSuffix("GET /cats -> Get all cats, OK (200)\n"))
+			})
+
+			It("should log the requests that do not match", func() {
+				recorder := httptest.NewRecorder()
+				handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/dogs", nil))
+				Expect(mockStdOut.String()).To(HaveSuffix(color.RedString("GET /dogs -> no matching request") + "\n"))
+			})
+
+			Context("when the documentation is redacted and truncated", func() {
+
+				BeforeEach(func() {
+					mockCommand.Config.Redact = true
+					mockCommand.Config.MaxArrayItems = 1
+				})
+
+				It("should build the collection with the original values", func() {
+					options := mockCollectionBuilder.Calls[0].Arguments.Get(1).(postman.BuilderOptions)
+					Expect(options.Documentation).To(BeFalse())
+				})
+
+			})
+
+		})
+
+		Context("when no collection is provided", func() {
+
+			BeforeEach(func() {
+				mockCommand.Config.CollectionFile = ""
+			})
+
+			It("should return an error", func() {
+				Expect(returnedError).NotTo(BeNil())
+				Expect(returnedError.Error()).To(Equal("You must provide a collection using the -collection flag"))
+			})
+
+		})
+
+		Context("when parsing the collection file fails", func() {
+
+			BeforeEach(func() {
+				mockCollectionBuilder.On("FromFile", any, any).Return(postman.Collection{}, errors.New("something bad happened!"))
+			})
+
+			It("should return an error", func() {
+				Expect(returnedError).NotTo(BeNil())
+				Expect(returnedError.Error()).To(Equal("Failed to parse collection file: something bad happened!"))
+			})
+
+			It("should not start the server", func() {
+				Expect(handler).To(BeNil())
+			})
+
+		})
+
+	})
+
+})
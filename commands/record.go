@@ -0,0 +1,103 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/aubm/postmanerator/configuration"
+	"github.com/aubm/postmanerator/postman"
+	"github.com/aubm/postmanerator/recorder"
+	"github.com/fatih/color"
+)
+
+const shutdownTimeout = 5 * time.Second
+
+type Record struct {
+	Config           *configuration.Configuration `inject:""`
+	CollectionWriter interface {
+		Write(w io.Writer, collection postman.Collection) error
+	} `inject:""`
+	Listener  net.Listener
+	Interrupt chan os.Signal
+}
+
+func (c *Record) Is(name string) bool {
+	return name == CmdRecord
+}
+
+func (c *Record) Do() error {
+	if c.Config.Upstream == "" {
+		return errors.New("You must provide the upstream server using the -upstream flag")
+	}
+	upstream, err := url.Parse(c.Config.Upstream)
+	if err != nil || !upstream.IsAbs() {
+		return fmt.Errorf("The upstream URL %v is not valid", c.Config.Upstream)
+	}
+
+	listener := c.Listener
+	if listener == nil {
+		if listener, err = net.Listen("tcp", c.Config.Listen); err != nil {
+			return fmt.Errorf("Failed to listen on %v: %v", c.Config.Listen, err)
+		}
+	}
+
+	interrupt := c.Interrupt
+	if interrupt == nil {
+		interrupt = make(chan os.Signal, 1)
+		signal.Notify(interrupt, os.Interrupt, syscall.SIGTERM)
+		defer signal.Stop(interrupt)
+	}
+
+	rec := recorder.NewRecorder(upstream)
+	server := &http.Server{Handler: rec}
+	serveErr := make(chan error, 1)
+	go func() {
+		serveErr <- server.Serve(listener)
+	}()
+
+	fmt.Fprintln(c.Config.Out, color.GreenString("Recording the calls to %v on %v, press Ctrl+C to stop", upstream, listener.Addr()))
+
+	select {
+	case err := <-serveErr:
+		return fmt.Errorf("Failed to serve: %v", err)
+	case <-interrupt:
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+	server.Shutdown(ctx)
+
+	return c.writeCollection(rec.Collection())
+}
+
+func (c *Record) writeCollection(collection postman.Collection) error {
+	fmt.Fprintf(c.Config.Out, "Writing the collection to %v... ", c.Config.RecordFile)
+	if err := c.writeCollectionFile(collection); err != nil {
+		fmt.Fprintln(c.Config.Out, color.RedString("FAIL. %v", err))
+		return err
+	}
+	fmt.Fprintln(c.Config.Out, color.GreenString("SUCCESS."))
+	return nil
+}
+
+func (c *Record) writeCollectionFile(collection postman.Collection) error {
+	f, err := os.Create(c.Config.RecordFile)
+	if err != nil {
+		return fmt.Errorf("Failed to create the collection file: %v", err)
+	}
+	defer f.Close()
+
+	if err := c.CollectionWriter.Write(f, collection); err != nil {
+		return fmt.Errorf("Failed to write the collection file: %v", err)
+	}
+	return nil
+}
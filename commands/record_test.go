@@ -0,0 +1,121 @@
+package commands_test
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+
+	. "github.com/aubm/postmanerator/commands"
+	"github.com/aubm/postmanerator/configuration"
+	"github.com/aubm/postmanerator/postman"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Record", func() {
+
+	var (
+		mockStdOut    *bytes.Buffer
+		upstream      *httptest.Server
+		listener      net.Listener
+		interrupt     chan os.Signal
+		dir           string
+		recordCommand *Record
+	)
+
+	BeforeEach(func() {
+		mockStdOut = new(bytes.Buffer)
+		upstream = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			fmt.Fprint(w, `{"name":"Tom"}`)
+		}))
+		listener = must(net.Listen("tcp", "127.0.0.1:0")).(net.Listener)
+		interrupt = make(chan os.Signal, 1)
+		dir = must(ioutil.TempDir("", "postmanerator-record")).(string)
+		recordCommand = &Record{
+			Config: &configuration.Configuration{
+				Out:        mockStdOut,
+				Upstream:   upstream.URL,
+				RecordFile: filepath.Join(dir, "collection.json"),
+			},
+			CollectionWriter: &postman.CollectionV210Writer{},
+			Listener:         listener,
+			Interrupt:        interrupt,
+		}
+	})
+
+	AfterEach(func() {
+		listener.Close()
+		upstream.Close()
+		os.RemoveAll(dir)
+	})
+
+	Describe("Is", func() {
+
+		It("should be OK", func() {
+			Expect(recordCommand.Is("cmd_record")).To(BeTrue())
+		})
+
+		It("should be KO", func() {
+			Expect(recordCommand.Is("cmd_verify")).To(BeFalse())
+		})
+
+	})
+
+	Describe("Do", func() {
+
+		It("should record the calls and write the collection when interrupted", func() {
+			done := make(chan error, 1)
+			go func() {
+				done <- recordCommand.Do()
+			}()
+
+			res := must(http.Get(fmt.Sprintf("http://%v/cats/42", listener.Addr()))).(*http.Response)
+			res.Body.Close()
+			interrupt <- os.Interrupt
+
+			Eventually(done).Should(Receive(BeNil()))
+			Expect(mockStdOut.String()).To(HaveSuffix("SUCCESS.\n"))
+
+			contents := []byte(readFileContents(recordCommand.Config.RecordFile))
+			collection := must((&postman.CollectionV210Parser{}).Parse(contents, postman.BuilderOptions{})).(postman.Collection)
+			Expect(collection.Folders).To(HaveLen(1))
+			Expect(collection.Folders[0].Requests[0].Name).To(Equal("GET /cats/:id"))
+			Expect(collection.Folders[0].Requests[0].Responses[0].Body).To(Equal(`{"name":"Tom"}`))
+		})
+
+		Context("when no upstream is provided", func() {
+
+			BeforeEach(func() {
+				recordCommand.Config.Upstream = ""
+			})
+
+			It("should return an error", func() {
+				err := recordCommand.Do()
+				Expect(err).NotTo(BeNil())
+				Expect(err.Error()).To(Equal("You must provide the upstream server using the -upstream flag"))
+			})
+
+		})
+
+		Context("when the upstream is not a valid URL", func() {
+
+			BeforeEach(func() {
+				recordCommand.Config.Upstream = "localhost"
+			})
+
+			It("should return an error", func() {
+				err := recordCommand.Do()
+				Expect(err).NotTo(BeNil())
+				Expect(err.Error()).To(Equal("The upstream URL localhost is not valid"))
+			})
+
+		})
+
+	})
+
+})
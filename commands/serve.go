@@ -0,0 +1,240 @@
+package commands
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"mime"
+	"net/http"
+	"path"
+	"strings"
+	"sync"
+
+	"github.com/aubm/postmanerator/configuration"
+	"github.com/aubm/postmanerator/postman"
+	"github.com/aubm/postmanerator/themes"
+	"github.com/fatih/color"
+)
+
+const (
+	defaultServePort = 8080
+	liveReloadPath   = "/_postmanerator/livereload"
+	liveReloadScript = `<script>new EventSource("` + liveReloadPath + `").onmessage = function () { location.reload(); };</script>`
+)
+
+type Serve struct {
+	Config *configuration.Configuration `inject:""`
+	Themes interface {
+		Open(themeName string) (*themes.Theme, error)
+		Download(themeName string) error
+		DownloadParents(themeName string) error
+	} `inject:""`
+	CollectionBuilder interface {
+		FromFile(file string, options postman.BuilderOptions) (postman.Collection, error)
+	} `inject:""`
+	EnvironmentBuilder interface {
+		FromFile(file string) (postman.Environment, error)
+	} `inject:""`
+	Renderer interface {
+		RenderPages(out themes.Output, theme *themes.Theme, collection postman.Collection) error
+	} `inject:""`
+	ListenAndServe func(addr string, handler http.Handler) error
+
+	mutex       sync.RWMutex
+	output      *themes.MemoryOutput
+	renderErr   error
+	subscribers map[chan struct{}]bool
+}
+
+func (c *Serve) Is(name string) bool {
+	return name == CmdServe
+}
+
+func (c *Serve) Do() error {
+	if c.Config.CollectionFile == "" {
+		return errors.New("You must provide a collection using the -collection flag")
+	}
+
+	theme, err := openTheme(c.Config, c.Themes)
+	if err != nil {
+		return err
+	}
+
+	c.generate()
+
+	watcher, err := newFileWatcher()
+	if err != nil {
+		return err
+	}
+	defer watcher.Close()
+
+	if err := watchSources(watcher, c.Config, theme); err != nil {
+		return err
+	}
+
+	go watcher.Run(func() {
+		c.generate()
+		c.reload()
+	}, func(err error) {
+		fmt.Fprintln(c.Config.Out, color.RedString("FAIL. %v", err))
+	})
+
+	port := c.Config.Port
+	if port == 0 {
+		port = defaultServePort
+	}
+	fmt.Fprintln(c.Config.Out, color.GreenString("Serving the documentation on http://localhost:%d", port))
+
+	listenAndServe := c.ListenAndServe
+	if listenAndServe == nil {
+		listenAndServe = http.ListenAndServe
+	}
+	return listenAndServe(fmt.Sprintf(":%d", port), c)
+}
+
+func (c *Serve) generate() {
+	fmt.Fprint(c.Config.Out, "Generating output... ")
+	output, err := c.render()
+
+	c.mutex.Lock()
+	if err == nil {
+		c.output = output
+	}
+	c.renderErr = err
+	c.mutex.Unlock()
+
+	if err != nil {
+		fmt.Fprintln(c.Config.Out, color.RedString("FAIL. %v", err))
+		return
+	}
+	fmt.Fprintln(c.Config.Out, color.GreenString("SUCCESS."))
+}
+
+func (c *Serve) render() (*themes.MemoryOutput, error) {
+	environment, err := buildEnvironment(c.Config, c.EnvironmentBuilder)
+	if err != nil {
+		return nil, err
+	}
+
+	collection, err := buildCollection(c.Config, c.CollectionBuilder, environment, true)
+	if err != nil {
+		return nil, err
+	}
+
+	// the theme is opened again so that new template files are taken into account
+	theme, err := c.Themes.Open(c.Config.UsedTheme)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to open the theme: %v", err)
+	}
+
+	output := themes.NewMemoryOutput()
+	if err := c.Renderer.RenderPages(output, theme, collection); err != nil {
+		return nil, err
+	}
+	return output, nil
+}
+
+func (c *Serve) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path == liveReloadPath {
+		c.serveLiveReload(w, r)
+		return
+	}
+
+	c.mutex.RLock()
+	output, renderErr := c.output, c.renderErr
+	c.mutex.RUnlock()
+
+	if output == nil {
+		http.Error(w, fmt.Sprintf("Failed to generate the documentation: %v", renderErr), http.StatusInternalServerError)
+		return
+	}
+
+	name := r.URL.Path
+	if strings.HasSuffix(name, "/") {
+		name += "index.html"
+	}
+	name = strings.TrimPrefix(path.Clean(name), "/")
+
+	contents, ok := output.File(name)
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+
+	contentType := mime.TypeByExtension(path.Ext(name))
+	if contentType == "" {
+		contentType = http.DetectContentType(contents)
+	}
+	if strings.HasPrefix(contentType, "text/html") {
+		contents = c.injectLiveReloadScript(contents)
+	}
+
+	w.Header().Set("Content-Type", contentType)
+	w.Write(contents)
+}
+
+func (c *Serve) injectLiveReloadScript(contents []byte) []byte {
+	i := bytes.LastIndex(contents, []byte("</body>"))
+	if i < 0 {
+		i = len(contents)
+	}
+
+	injected := make([]byte, 0, len(contents)+len(liveReloadScript))
+	injected = append(injected, contents[:i]...)
+	injected = append(injected, liveReloadScript...)
+	return append(injected, contents[i:]...)
+}
+
+// serveLiveReload streams server-sent events, each one asking the page to reload.
+func (c *Serve) serveLiveReload(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "Streaming is not supported", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+
+	reload := c.subscribe()
+	defer c.unsubscribe(reload)
+	flusher.Flush()
+
+	for {
+		select {
+		case <-reload:
+			fmt.Fprint(w, "data: reload\n\n")
+			flusher.Flush()
+		case <-r.Context().Done():
+			return
+		}
+	}
+}
+
+func (c *Serve) subscribe() chan struct{} {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if c.subscribers == nil {
+		c.subscribers = make(map[chan struct{}]bool)
+	}
+	reload := make(chan struct{}, 1)
+	c.subscribers[reload] = true
+	return reload
+}
+
+func (c *Serve) unsubscribe(reload chan struct{}) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	delete(c.subscribers, reload)
+}
+
+func (c *Serve) reload() {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+	for reload := range c.subscribers {
+		select {
+		case reload <- struct{}{}:
+		default:
+		}
+	}
+}
@@ -0,0 +1,334 @@
+package commands_test
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path"
+	"time"
+
+	. "github.com/aubm/postmanerator/commands"
+	"github.com/aubm/postmanerator/configuration"
+	"github.com/aubm/postmanerator/postman"
+	. "github.com/aubm/postmanerator/postman/mocks"
+	"github.com/aubm/postmanerator/themes"
+	. "github.com/aubm/postmanerator/themes/mocks"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/satori/go.uuid"
+	"github.com/stretchr/testify/mock"
+)
+
+var _ = Describe("Serve", func() {
+
+	var (
+		mockStdOut             *bytes.Buffer
+		tmpDirectory           string
+		mockThemeManager       *MockThemeManager
+		mockThemeRenderer      *MockThemeRenderer
+		mockCollectionBuilder  *MockCollectionBuilder
+		mockEnvironmentBuilder *MockEnvironmentBuilder
+		listenedAddr           string
+		handler                http.Handler
+		whileServing           func()
+		serveCommand           *Serve
+	)
+
+	BeforeEach(func() {
+		mockStdOut = new(bytes.Buffer)
+		tmpDirectory = path.Join(os.TempDir(), uuid.NewV4().String())
+		must(nil, os.MkdirAll(path.Join(tmpDirectory, "theme"), 0777))
+		putFileContents(path.Join(tmpDirectory, "collection.json"), "{}")
+		mockThemeManager = &MockThemeManager{}
+		mockThemeRenderer = &MockThemeRenderer{}
+		mockCollectionBuilder = &MockCollectionBuilder{}
+		mockEnvironmentBuilder = &MockEnvironmentBuilder{}
+		listenedAddr = ""
+		handler = nil
+		whileServing = func() {}
+		serveCommand = &Serve{
+			Config: &configuration.Configuration{
+				Out:            mockStdOut,
+				UsedTheme:      "default",
+				CollectionFile: path.Join(tmpDirectory, "collection.json"),
+			},
+			Themes:             mockThemeManager,
+			Renderer:           mockThemeRenderer,
+			CollectionBuilder:  mockCollectionBuilder,
+			EnvironmentBuilder: mockEnvironmentBuilder,
+			ListenAndServe: func(addr string, h http.Handler) error {
+				listenedAddr = addr
+				handler = h
+				whileServing()
+				return nil
+			},
+		}
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(tmpDirectory)
+	})
+
+	Describe("Is", func() {
+
+		It("should be OK", func() {
+			Expect(serveCommand.Is("cmd_serve")).To(BeTrue())
+		})
+
+		It("should be KO", func() {
+			Expect(serveCommand.Is("cmd_default")).To(BeFalse())
+		})
+
+	})
+
+	Describe("Do", func() {
+
+		var returnedError error
+
+		JustBeforeEach(func() {
+			returnedError = serveCommand.Do()
+		})
+
+		get := func(url string) *httptest.ResponseRecorder {
+			recorder := httptest.NewRecorder()
+			handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, url, nil))
+			return recorder
+		}
+
+		Context("when everything is ok", func() {
+
+			var (
+				collection postman.Collection
+				theme      *themes.Theme
+			)
+
+			BeforeEach(func() {
+				collection = postman.Collection{Name: "foo"}
+				theme = &themes.Theme{Name: "default", Path: path.Join(tmpDirectory, "theme")}
+				mockCollectionBuilder.On("FromFile", any, any).Return(collection, nil)
+				mockThemeManager.On("Open", any).Return(theme, nil)
+				mockThemeRenderer.On("RenderPages", any, any, any).Return(nil).Run(func(args mock.Arguments) {
+					out := args.Get(0).(themes.Output)
+					for name, contents := range map[string]string{
+						"index.html":       "<html><body>Hello</body></html>",
+						"dogs/index.html":  "Dogs",
+						"assets/style.css": "body {}",
+					} {
+						w, _ := out.Create(name)
+						fmt.Fprint(w, contents)
+						w.Close()
+					}
+				})
+			})
+
+			It("should not return an error", func() {
+				Expect(returnedError).To(BeNil())
+			})
+
+			It("should listen on the default port", func() {
+				Expect(listenedAddr).To(Equal(":8080"))
+			})
+
+			It("should produce the right command output", func() {
+				Expect(mockStdOut.String()).To(ContainSubstring("SUCCESS."))
+				Expect(mockStdOut.String()).To(ContainSubstring("Serving the documentation on http://localhost:8080"))
+			})
+
+			It("should serve the index page with the live reload script", func() {
+				res := get("/")
+				Expect(res.Code).To(Equal(http.StatusOK))
+				Expect(res.Header().Get("Content-Type")).To(HavePrefix("text/html"))
+				Expect(res.Body.String()).To(HavePrefix("<html><body>Hello<script>new EventSource("))
+				Expect(res.Body.String()).To(HaveSuffix("</script></body></html>"))
+			})
+
+			It("should serve pages of sub directories", func() {
+				res := get("/dogs/")
+				Expect(res.Code).To(Equal(http.StatusOK))
+				Expect(res.Body.String()).To(HavePrefix("Dogs<script>"))
+			})
+
+			It("should serve assets untouched", func() {
+				res := get("/assets/style.css")
+				Expect(res.Code).To(Equal(http.StatusOK))
+				Expect(res.Header().Get("Content-Type")).To(HavePrefix("text/css"))
+				Expect(res.Body.String()).To(Equal("body {}"))
+			})
+
+			It("should respond with a 404 for unknown files", func() {
+				Expect(get("/unknown.html").Code).To(Equal(http.StatusNotFound))
+			})
+
+			Context("and a custom port is specified", func() {
+
+				BeforeEach(func() {
+					serveCommand.Config.Port = 9000
+				})
+
+				It("should listen on the custom port", func() {
+					Expect(listenedAddr).To(Equal(":9000"))
+				})
+
+			})
+
+			Context("while the server is running", func() {
+
+				var (
+					receivedEvents chan string
+					changeFiles    func()
+				)
+
+				BeforeEach(func() {
+					receivedEvents = make(chan string, 10)
+					changeFiles = func() {}
+					whileServing = func() {
+						server := httptest.NewServer(handler)
+						defer server.Close()
+
+						res, err := http.Get(server.URL + "/_postmanerator/livereload")
+						Expect(err).To(BeNil())
+						defer res.Body.Close()
+						go func() {
+							reader := bufio.NewReader(res.Body)
+							for {
+								line, err := reader.ReadString('\n')
+								if err != nil {
+									return
+								}
+								if line != "\n" {
+									receivedEvents <- line
+								}
+							}
+						}()
+
+						changeFiles()
+						Eventually(receivedEvents, "3s").Should(Receive(Equal("data: reload\n")))
+						Consistently(receivedEvents, "500ms").ShouldNot(Receive())
+					}
+				})
+
+				Context("and the collection file changes", func() {
+
+					BeforeEach(func() {
+						changeFiles = func() {
+							putFileContents(serveCommand.Config.CollectionFile, `{"info": {}}`)
+						}
+					})
+
+					It("should rebuild the collection", func() {
+						Expect(len(mockCollectionBuilder.Calls)).To(Equal(2))
+					})
+
+				})
+
+				Context("and the collection file changes many times in a row", func() {
+
+					BeforeEach(func() {
+						changeFiles = func() {
+							for i := 0; i < 5; i++ {
+								putFileContents(serveCommand.Config.CollectionFile, fmt.Sprintf(`{"info": {"name": "%d"}}`, i))
+							}
+						}
+					})
+
+					It("should rebuild the collection only once", func() {
+						Expect(len(mockCollectionBuilder.Calls)).To(Equal(2))
+					})
+
+				})
+
+				Context("and a file changes in a nested directory of the theme", func() {
+
+					BeforeEach(func() {
+						must(nil, os.MkdirAll(path.Join(tmpDirectory, "theme", "partials"), 0777))
+						changeFiles = func() {
+							putFileContents(path.Join(tmpDirectory, "theme", "partials", "menu.tpl"), "menu")
+						}
+					})
+
+					It("should render the documentation again", func() {
+						Expect(len(mockThemeRenderer.Calls)).To(Equal(2))
+					})
+
+				})
+
+				Context("and a file changes in a directory created in the theme afterwards", func() {
+
+					BeforeEach(func() {
+						changeFiles = func() {
+							must(nil, os.MkdirAll(path.Join(tmpDirectory, "theme", "partials"), 0777))
+							Eventually(receivedEvents, "3s").Should(Receive(Equal("data: reload\n")))
+							putFileContents(path.Join(tmpDirectory, "theme", "partials", "menu.tpl"), "menu")
+						}
+					})
+
+					It("should render the documentation again for each change", func() {
+						Expect(len(mockThemeRenderer.Calls)).To(Equal(3))
+					})
+
+				})
+
+				Context("and an unrelated file changes next to the collection file", func() {
+
+					BeforeEach(func() {
+						whileServing = func() {
+							putFileContents(path.Join(tmpDirectory, "notes.txt"), "unrelated")
+							time.Sleep(500 * time.Millisecond)
+						}
+					})
+
+					It("should not rebuild anything", func() {
+						Expect(len(mockCollectionBuilder.Calls)).To(Equal(1))
+					})
+
+				})
+
+			})
+
+		})
+
+		Context("when no collection is provided", func() {
+
+			BeforeEach(func() {
+				serveCommand.Config.CollectionFile = ""
+			})
+
+			It("should return an error", func() {
+				Expect(returnedError).NotTo(BeNil())
+				Expect(returnedError.Error()).To(Equal("You must provide a collection using the -collection flag"))
+			})
+
+			It("should not start the server", func() {
+				Expect(handler).To(BeNil())
+			})
+
+		})
+
+		Context("when rendering fails", func() {
+
+			BeforeEach(func() {
+				mockCollectionBuilder.On("FromFile", any, any).Return(postman.Collection{}, nil)
+				mockThemeManager.On("Open", any).Return(&themes.Theme{Path: path.Join(tmpDirectory, "theme")}, nil)
+				mockThemeRenderer.On("RenderPages", any, any, any).Return(errors.New("something bad happened!"))
+			})
+
+			It("should still start the server", func() {
+				Expect(returnedError).To(BeNil())
+				Expect(handler).NotTo(BeNil())
+			})
+
+			It("should respond with the error", func() {
+				res := get("/")
+				Expect(res.Code).To(Equal(http.StatusInternalServerError))
+				Expect(res.Body.String()).To(ContainSubstring("something bad happened!"))
+			})
+
+		})
+
+	})
+
+})
@@ -0,0 +1,118 @@
+package commands
+
+import (
+	"fmt"
+
+	"github.com/aubm/postmanerator/configuration"
+	"github.com/aubm/postmanerator/postman"
+	"github.com/aubm/postmanerator/themes"
+	"github.com/fatih/color"
+)
+
+type themeOpener interface {
+	Open(themeName string) (*themes.Theme, error)
+	Download(themeName string) error
+	DownloadParents(themeName string) error
+}
+
+type collectionBuilder interface {
+	FromFile(file string, options postman.BuilderOptions) (postman.Collection, error)
+}
+
+type environmentBuilder interface {
+	FromFile(file string) (postman.Environment, error)
+}
+
+func buildEnvironment(config *configuration.Configuration, builder environmentBuilder) (environment postman.Environment, err error) {
+	if config.EnvironmentFile == "" {
+		return
+	}
+
+	environment, err = builder.FromFile(config.EnvironmentFile)
+	if err != nil {
+		err = fmt.Errorf("Failed to parse environment file: %v", err)
+	}
+
+	return
+}
+
+// buildCollection builds the collection of the -collection flag. documentation is set by the commands generating a
+// documentation, which are the only ones filtering the headers, redacting the secrets and truncating the bodies.
+func buildCollection(config *configuration.Configuration, builder collectionBuilder, environment postman.Environment, documentation bool) (postman.Collection, error) {
+	options := postman.BuilderOptions{
+		Documentation:          documentation,
+		IgnoredRequestHeaders:  config.IgnoredRequestHeaders.Values,
+		IgnoredResponseHeaders: config.IgnoredResponseHeaders.Values,
+		AllowedRequestHeaders:  config.AllowedRequestHeaders.Values,
+		AllowedResponseHeaders: config.AllowedResponseHeaders.Values,
+		EnvironmentVariables:   environment,
+		StructuresFile:         config.StructuresFile,
+		InferStructures:        config.InferStructures,
+		Include:                config.Include.Values,
+		Exclude:                config.Exclude.Values,
+		Tags:                   config.Tags.Values,
+		Sort:                   config.Sort,
+		Redact:                 config.Redact,
+		RedactPatterns:         config.RedactPatterns.Values,
+		MaxBodySize:            config.MaxBodySize,
+		MaxArrayItems:          config.MaxArrayItems,
+		Strict:                 config.Strict,
+	}
+	postmanCollection, err := builder.FromFile(config.CollectionFile, options)
+	if err != nil {
+		return postman.Collection{}, fmt.Errorf("Failed to parse collection file: %v", err)
+	}
+
+	printWarnings(config, postmanCollection.Warnings)
+	printRedactions(config, postmanCollection.Redactions)
+	return postmanCollection, nil
+}
+
+// printWarnings prints the collection warnings on the error output, so that they do not end up in a generated
+// documentation written on the standard output.
+func printWarnings(config *configuration.Configuration, warnings []string) {
+	if config.Err == nil {
+		return
+	}
+	for _, warning := range warnings {
+		fmt.Fprintln(config.Err, color.YellowString("Warning: %v", warning))
+	}
+}
+
+// printRedactions reports the redacted secrets on the error output.
+func printRedactions(config *configuration.Configuration, redactions []postman.Redaction) {
+	if config.Err == nil {
+		return
+	}
+	for _, redaction := range redactions {
+		fmt.Fprintf(config.Err, "Redacted %v (%v)\n", redaction.Location, redaction.Rule)
+	}
+}
+
+func openTheme(config *configuration.Configuration, opener themeOpener) (*themes.Theme, error) {
+	usedTheme := config.UsedTheme
+
+	theme, err := opener.Open(usedTheme)
+	if err == nil {
+		return theme, nil
+	}
+
+	if missing, ok := err.(*themes.MissingParentError); ok {
+		fmt.Fprintln(config.Out, color.BlueString("Theme '%v' extends '%v', which is not installed, trying to download it...", missing.Theme, missing.Parent))
+		if err := opener.DownloadParents(usedTheme); err != nil {
+			return nil, err
+		}
+		return opener.Open(usedTheme)
+	}
+
+	if err != themes.ErrThemeNotFound {
+		return nil, fmt.Errorf("Failed to open the theme: %v", err)
+	}
+
+	fmt.Fprintln(config.Out, color.BlueString("Theme '%v' not found, trying to download it...", usedTheme))
+	if err := opener.Download(usedTheme); err != nil {
+		return nil, err
+	}
+
+	return opener.Open(usedTheme)
+}
@@ -0,0 +1,97 @@
+package commands
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/aubm/postmanerator/configuration"
+	"github.com/aubm/postmanerator/postman"
+	"github.com/fatih/color"
+)
+
+type Sunset struct {
+	Config            *configuration.Configuration `inject:""`
+	CollectionBuilder interface {
+		FromFile(file string, options postman.BuilderOptions) (postman.Collection, error)
+	} `inject:""`
+	EnvironmentBuilder interface {
+		FromFile(file string) (postman.Environment, error)
+	} `inject:""`
+	// Now returns the current time, default is time.Now.
+	Now func() time.Time
+}
+
+func (c *Sunset) Is(name string) bool {
+	return name == CmdSunset
+}
+
+func (c *Sunset) Do() error {
+	if c.Config.CollectionFile == "" {
+		return errors.New("You must provide a collection using the -collection flag")
+	}
+
+	environment, err := buildEnvironment(c.Config, c.EnvironmentBuilder)
+	if err != nil {
+		return err
+	}
+
+	collection, err := buildCollection(c.Config, c.CollectionBuilder, environment, false)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	if c.Now != nil {
+		now = c.Now()
+	}
+
+	deprecated, past := 0, 0
+	folder := postman.Folder{Requests: collection.Requests, Folders: collection.Folders}
+	for _, request := range deprecatedRequests(folder, "") {
+		deprecated++
+		status := color.YellowString("DEPRECATED")
+		if request.Lifecycle.PastSunset(now) {
+			past++
+			status = color.RedString("PAST SUNSET")
+		}
+		fmt.Fprintf(c.Config.Out, "%v %v %v (%v)%v\n", status, request.Method, request.Path(), request.Name, describeLifecycle(request.Lifecycle))
+	}
+	fmt.Fprintf(c.Config.Out, "\n%d deprecated, %d past their sunset date\n", deprecated, past)
+
+	if past > 0 {
+		return fmt.Errorf("%d endpoint(s) are past their sunset date", past)
+	}
+	return nil
+}
+
+func deprecatedRequests(folder postman.Folder, path string) []postman.Request {
+	requests := make([]postman.Request, 0)
+	for _, request := range folder.Requests {
+		if request.Lifecycle.Deprecated {
+			if path != "" {
+				request.Name = path + " / " + request.Name
+			}
+			requests = append(requests, request)
+		}
+	}
+	for _, f := range folder.Folders {
+		name := f.Name
+		if path != "" {
+			name = path + " / " + f.Name
+		}
+		requests = append(requests, deprecatedRequests(f, name)...)
+	}
+	return requests
+}
+
+func describeLifecycle(lifecycle postman.Lifecycle) string {
+	description := ""
+	if lifecycle.Sunset != nil {
+		description += fmt.Sprintf(", sunset on %v", lifecycle.Sunset.Format("2006-01-02"))
+	}
+	if lifecycle.ReplacedBy != "" {
+		description += fmt.Sprintf(", replaced by %v", lifecycle.ReplacedBy)
+	}
+	return description
+}
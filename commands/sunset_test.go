@@ -0,0 +1,131 @@
+package commands_test
+
+import (
+	"bytes"
+	"errors"
+	"time"
+
+	. "github.com/aubm/postmanerator/commands"
+	"github.com/aubm/postmanerator/configuration"
+	"github.com/aubm/postmanerator/postman"
+	. "github.com/aubm/postmanerator/postman/mocks"
+	"github.com/fatih/color"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Sunset", func() {
+
+	var (
+		mockStdOut            *bytes.Buffer
+		mockCollectionBuilder *MockCollectionBuilder
+		sunsetCommand         *Sunset
+	)
+
+	BeforeEach(func() {
+		mockStdOut = new(bytes.Buffer)
+		mockCollectionBuilder = &MockCollectionBuilder{}
+		sunsetCommand = &Sunset{
+			Config: &configuration.Configuration{
+				Out:            mockStdOut,
+				CollectionFile: "awesome-collection.json",
+			},
+			CollectionBuilder:  mockCollectionBuilder,
+			EnvironmentBuilder: &MockEnvironmentBuilder{},
+			Now: func() time.Time {
+				return time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+			},
+		}
+	})
+
+	Describe("Is", func() {
+
+		It("should be OK", func() {
+			Expect(sunsetCommand.Is("cmd_sunset")).To(BeTrue())
+		})
+
+		It("should be KO", func() {
+			Expect(sunsetCommand.Is("cmd_test")).To(BeFalse())
+		})
+
+	})
+
+	Describe("Do", func() {
+
+		var (
+			returnedError error
+			sunset        time.Time
+		)
+
+		BeforeEach(func() {
+			sunset = time.Date(2024, 12, 31, 0, 0, 0, 0, time.UTC)
+		})
+
+		JustBeforeEach(func() {
+			mockCollectionBuilder.On("FromFile", any, any).Return(postman.Collection{
+				Name: "Cats API",
+				Requests: []postman.Request{
+					{Name: "Get all cats", Method: "GET", URL: "{{url}}/api/cats", Lifecycle: postman.Lifecycle{Deprecated: true}},
+					{Name: "Get all cats v2", Method: "GET", URL: "{{url}}/api/v2/cats"},
+				},
+				Folders: []postman.Folder{
+					{
+						Name: "Dogs",
+						Requests: []postman.Request{
+							{Name: "Delete a dog", Method: "DELETE", URL: "http://localhost/api/dogs/:id", Lifecycle: postman.Lifecycle{
+								Deprecated: true, Sunset: &sunset, ReplacedBy: "Remove a dog",
+							}},
+						},
+					},
+				},
+			}, nil)
+			returnedError = sunsetCommand.Do()
+		})
+
+		Context("when no endpoint is past its sunset date", func() {
+
+			It("should not return an error", func() {
+				Expect(returnedError).To(BeNil())
+			})
+
+			It("should list the deprecated endpoints", func() {
+				Expect(mockStdOut.String()).To(Equal(
+					color.YellowString("DEPRECATED") + " GET /api/cats (Get all cats)\n" +
+						color.YellowString("DEPRECATED") + " DELETE /api/dogs/:id (Dogs / Delete a dog), sunset on 2024-12-31, replaced by Remove a dog\n" +
+						"\n2 deprecated, 0 past their sunset date\n"))
+			})
+
+		})
+
+		Context("when an endpoint is past its sunset date", func() {
+
+			BeforeEach(func() {
+				sunset = time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+			})
+
+			It("should return an error", func() {
+				Expect(returnedError).NotTo(BeNil())
+				Expect(returnedError.Error()).To(Equal("1 endpoint(s) are past their sunset date"))
+			})
+
+			It("should highlight the endpoint", func() {
+				Expect(mockStdOut.String()).To(ContainSubstring(
+					color.RedString("PAST SUNSET") + " DELETE /api/dogs/:id (Dogs / Delete a dog), sunset on 2024-06-01, replaced by Remove a dog\n"))
+			})
+
+		})
+
+	})
+
+	Describe("Do with a broken collection", func() {
+
+		It("should return an error", func() {
+			mockCollectionBuilder.On("FromFile", any, any).Return(postman.Collection{}, errors.New("something bad happened!"))
+			err := sunsetCommand.Do()
+			Expect(err).NotTo(BeNil())
+			Expect(err.Error()).To(Equal("Failed to parse collection file: something bad happened!"))
+		})
+
+	})
+
+})
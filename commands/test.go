@@ -0,0 +1,113 @@
+package commands
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/aubm/postmanerator/configuration"
+	"github.com/aubm/postmanerator/postman"
+	"github.com/aubm/postmanerator/sandbox"
+	"github.com/aubm/postmanerator/verify"
+)
+
+type Test struct {
+	Config            *configuration.Configuration `inject:""`
+	CollectionBuilder interface {
+		FromFile(file string, options postman.BuilderOptions) (postman.Collection, error)
+	} `inject:""`
+	EnvironmentBuilder interface {
+		FromFile(file string) (postman.Environment, error)
+	} `inject:""`
+}
+
+func (c *Test) Is(name string) bool {
+	return name == CmdTest
+}
+
+func (c *Test) Do() error {
+	if c.Config.CollectionFile == "" {
+		return errors.New("You must provide a collection using the -collection flag")
+	}
+
+	environment, err := buildEnvironment(c.Config, c.EnvironmentBuilder)
+	if err != nil {
+		return err
+	}
+
+	collection, err := buildCollection(c.Config, c.CollectionBuilder, environment, false)
+	if err != nil {
+		return err
+	}
+
+	report := verify.Report{Name: collection.Name, Cases: make([]verify.Case, 0)}
+	folder := postman.Folder{Requests: collection.Requests, Folders: collection.Folders}
+	if err := c.testFolder(&report, &sandbox.Sandbox{}, collection, collection.Name, folder); err != nil {
+		return err
+	}
+
+	if err := report.WriteText(c.Config.Out); err != nil {
+		return fmt.Errorf("Failed to write the report: %v", err)
+	}
+
+	if c.Config.JUnitFile != "" {
+		if err := writeJUnitReport(c.Config.JUnitFile, report); err != nil {
+			return err
+		}
+	}
+
+	if failed := report.Failed(); failed > 0 {
+		return fmt.Errorf("%d of %d saved responses failed their tests", failed, len(report.Cases))
+	}
+	return nil
+}
+
+func (c *Test) testFolder(report *verify.Report, sb *sandbox.Sandbox, collection postman.Collection, suite string, folder postman.Folder) error {
+	for _, request := range folder.Requests {
+		chain, err := collection.ScriptChain(request.ID)
+		if err != nil {
+			return fmt.Errorf("Failed to find the scripts of %v: %v", request.Name, err)
+		}
+		if len(chain.Tests) == 0 {
+			continue
+		}
+		if len(request.Responses) == 0 {
+			report.Cases = append(report.Cases, verify.Case{Suite: suite, Name: request.Name, Skipped: true})
+			continue
+		}
+		for _, response := range request.Responses {
+			report.Cases = append(report.Cases, c.testResponse(sb, chain.Tests, suite, request, response))
+		}
+	}
+	for _, subFolder := range folder.Folders {
+		if err := c.testFolder(report, sb, collection, suite+"/"+subFolder.Name, subFolder); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (c *Test) testResponse(sb *sandbox.Sandbox, scripts []postman.Script, suite string, request postman.Request, response postman.Response) verify.Case {
+	testCase := verify.Case{Suite: suite, Name: fmt.Sprintf("%v - %v", request.Name, response.Name)}
+
+	sentRequest := request
+	if response.OriginalRequest != nil {
+		sentRequest = *response.OriginalRequest
+		sentRequest.Name = request.Name
+	}
+
+	start := time.Now()
+	for _, script := range scripts {
+		results, err := sb.Run(script.Source, sentRequest, response)
+		for _, result := range results {
+			if !result.Passed {
+				testCase.Failures = append(testCase.Failures, fmt.Sprintf("%v: %v", result.Name, result.Message))
+			}
+		}
+		if err != nil {
+			testCase.Failures = append(testCase.Failures, fmt.Sprintf("script error in %v: %v", script.Owner, err))
+		}
+	}
+	testCase.Duration = time.Since(start)
+	return testCase
+}
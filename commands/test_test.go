@@ -0,0 +1,160 @@
+package commands_test
+
+import (
+	"bytes"
+	"errors"
+
+	. "github.com/aubm/postmanerator/commands"
+	"github.com/aubm/postmanerator/configuration"
+	"github.com/aubm/postmanerator/postman"
+	. "github.com/aubm/postmanerator/postman/mocks"
+	"github.com/fatih/color"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Test", func() {
+
+	var (
+		mockStdOut            *bytes.Buffer
+		mockCollectionBuilder *MockCollectionBuilder
+		testCommand           *Test
+	)
+
+	BeforeEach(func() {
+		mockStdOut = new(bytes.Buffer)
+		mockCollectionBuilder = &MockCollectionBuilder{}
+		testCommand = &Test{
+			Config: &configuration.Configuration{
+				Out:            mockStdOut,
+				CollectionFile: "awesome-collection.json",
+			},
+			CollectionBuilder:  mockCollectionBuilder,
+			EnvironmentBuilder: &MockEnvironmentBuilder{},
+		}
+	})
+
+	Describe("Is", func() {
+
+		It("should be OK", func() {
+			Expect(testCommand.Is("cmd_test")).To(BeTrue())
+		})
+
+		It("should be KO", func() {
+			Expect(testCommand.Is("cmd_verify")).To(BeFalse())
+		})
+
+	})
+
+	Describe("Do", func() {
+
+		var (
+			returnedError error
+			tests         string
+			folderTests   string
+		)
+
+		BeforeEach(func() {
+			tests = `pm.test("Status code is 200", function () { pm.response.to.have.status(200); });`
+			folderTests = ""
+		})
+
+		JustBeforeEach(func() {
+			mockCollectionBuilder.On("FromFile", any, any).Return(postman.Collection{
+				Name: "Cats API",
+				Requests: []postman.Request{
+					{ID: "1", Name: "Get all cats", Tests: tests, Responses: []postman.Response{{Name: "OK", StatusCode: 200}}},
+					{ID: "2", Name: "Get all dogs", Responses: []postman.Response{{Name: "OK", StatusCode: 200}}},
+				},
+				Folders: []postman.Folder{
+					{
+						Name:     "Birds",
+						Requests: []postman.Request{{ID: "3", Name: "Get all birds", Tests: tests}},
+					},
+					{
+						Name:  "Fishes",
+						Tests: folderTests,
+						Requests: []postman.Request{
+							{ID: "4", Name: "Get all fishes", Responses: []postman.Response{{Name: "OK", StatusCode: 200, Body: "[]"}}},
+						},
+					},
+				},
+			}, nil)
+			returnedError = testCommand.Do()
+		})
+
+		Context("when the saved responses pass their tests", func() {
+
+			It("should not return an error", func() {
+				Expect(returnedError).To(BeNil())
+			})
+
+			It("should print the report", func() {
+				Expect(mockStdOut.String()).To(Equal(
+					color.GreenString("PASS") + " Cats API / Get all cats - OK\n" +
+						color.YellowString("SKIP") + " Cats API/Birds / Get all birds (no saved response)\n" +
+						"\n1 passed, 0 failed, 1 skipped\n"))
+			})
+
+		})
+
+		Context("when a saved response fails its tests", func() {
+
+			BeforeEach(func() {
+				tests = `pm.test("Status code is 201", function () { pm.response.to.have.status(201); });`
+			})
+
+			It("should return an error", func() {
+				Expect(returnedError).NotTo(BeNil())
+				Expect(returnedError.Error()).To(Equal("1 of 2 saved responses failed their tests"))
+			})
+
+			It("should print the failed assertions", func() {
+				Expect(mockStdOut.String()).To(ContainSubstring(
+					"     Status code is 201: expected response to have status code 201 but got 200\n"))
+			})
+
+		})
+
+		Context("when a test script is invalid", func() {
+
+			BeforeEach(func() {
+				tests = `var x = ;`
+			})
+
+			It("should report the script error", func() {
+				Expect(returnedError).NotTo(BeNil())
+				Expect(mockStdOut.String()).To(ContainSubstring("     script error in Get all cats: "))
+			})
+
+		})
+
+		Context("when a folder has tests", func() {
+
+			BeforeEach(func() {
+				folderTests = `pm.test("Body is not empty", function () { pm.expect(pm.response.json()).not.to.be.empty; });`
+			})
+
+			It("should run them against the responses of the folder requests", func() {
+				Expect(returnedError).NotTo(BeNil())
+				Expect(returnedError.Error()).To(Equal("1 of 3 saved responses failed their tests"))
+				Expect(mockStdOut.String()).To(ContainSubstring(color.RedString("FAIL") + " Cats API/Fishes / Get all fishes - OK\n" +
+					"     Body is not empty: expected [] not to be empty\n"))
+			})
+
+		})
+
+	})
+
+	Describe("Do with a broken collection", func() {
+
+		It("should return an error", func() {
+			mockCollectionBuilder.On("FromFile", any, any).Return(postman.Collection{}, errors.New("something bad happened!"))
+			err := testCommand.Do()
+			Expect(err).NotTo(BeNil())
+			Expect(err.Error()).To(Equal("Failed to parse collection file: something bad happened!"))
+		})
+
+	})
+
+})
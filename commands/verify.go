@@ -0,0 +1,74 @@
+package commands
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+
+	"github.com/aubm/postmanerator/configuration"
+	"github.com/aubm/postmanerator/postman"
+	"github.com/aubm/postmanerator/verify"
+)
+
+type Verify struct {
+	Config            *configuration.Configuration `inject:""`
+	CollectionBuilder interface {
+		FromFile(file string, options postman.BuilderOptions) (postman.Collection, error)
+	} `inject:""`
+	EnvironmentBuilder interface {
+		FromFile(file string) (postman.Environment, error)
+	} `inject:""`
+	Client *http.Client
+}
+
+func (c *Verify) Is(name string) bool {
+	return name == CmdVerify
+}
+
+func (c *Verify) Do() error {
+	if c.Config.CollectionFile == "" {
+		return errors.New("You must provide a collection using the -collection flag")
+	}
+
+	environment, err := buildEnvironment(c.Config, c.EnvironmentBuilder)
+	if err != nil {
+		return err
+	}
+
+	collection, err := buildCollection(c.Config, c.CollectionBuilder, environment, false)
+	if err != nil {
+		return err
+	}
+
+	runner := &verify.Runner{BaseURL: c.Config.BaseURL, Exact: c.Config.ExactMatch, Client: c.Client}
+	report := runner.Run(collection)
+
+	if err := report.WriteText(c.Config.Out); err != nil {
+		return fmt.Errorf("Failed to write the report: %v", err)
+	}
+
+	if c.Config.JUnitFile != "" {
+		if err := writeJUnitReport(c.Config.JUnitFile, report); err != nil {
+			return err
+		}
+	}
+
+	if failed := report.Failed(); failed > 0 {
+		return fmt.Errorf("%d of %d contract tests failed", failed, len(report.Cases))
+	}
+	return nil
+}
+
+func writeJUnitReport(file string, report verify.Report) error {
+	f, err := os.Create(file)
+	if err != nil {
+		return fmt.Errorf("Failed to create the JUnit report: %v", err)
+	}
+	defer f.Close()
+
+	if err := report.WriteJUnit(f); err != nil {
+		return fmt.Errorf("Failed to write the JUnit report: %v", err)
+	}
+	return nil
+}
@@ -0,0 +1,175 @@
+package commands_test
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+
+	. "github.com/aubm/postmanerator/commands"
+	"github.com/aubm/postmanerator/configuration"
+	"github.com/aubm/postmanerator/postman"
+	. "github.com/aubm/postmanerator/postman/mocks"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Verify", func() {
+
+	var (
+		mockStdOut            *bytes.Buffer
+		mockCollectionBuilder *MockCollectionBuilder
+		server                *httptest.Server
+		statusCode            int
+		verifyCommand         *Verify
+	)
+
+	BeforeEach(func() {
+		mockStdOut = new(bytes.Buffer)
+		mockCollectionBuilder = &MockCollectionBuilder{}
+		statusCode = 200
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(statusCode)
+			fmt.Fprint(w, `[{"name":"Barney"}]`)
+		}))
+		verifyCommand = &Verify{
+			Config: &configuration.Configuration{
+				Out:            mockStdOut,
+				CollectionFile: "awesome-collection.json",
+				BaseURL:        server.URL,
+			},
+			CollectionBuilder:  mockCollectionBuilder,
+			EnvironmentBuilder: &MockEnvironmentBuilder{},
+		}
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	Describe("Is", func() {
+
+		It("should be OK", func() {
+			Expect(verifyCommand.Is("cmd_verify")).To(BeTrue())
+		})
+
+		It("should be KO", func() {
+			Expect(verifyCommand.Is("cmd_mock")).To(BeFalse())
+		})
+
+	})
+
+	Describe("Do", func() {
+
+		var returnedError error
+
+		JustBeforeEach(func() {
+			returnedError = verifyCommand.Do()
+		})
+
+		Context("when the collection is parsed", func() {
+
+			BeforeEach(func() {
+				mockCollectionBuilder.On("FromFile", any, any).Return(postman.Collection{
+					Name: "Cats API",
+					Requests: []postman.Request{{
+						Name:      "Get all cats",
+						Method:    "GET",
+						URL:       "{{url}}/cats",
+						Responses: []postman.Response{{Name: "OK", StatusCode: 200, Body: `[{"name":"Sam"}]`}},
+					}},
+				}, nil)
+			})
+
+			It("should not return an error", func() {
+				Expect(returnedError).To(BeNil())
+			})
+
+			It("should print the report", func() {
+				Expect(mockStdOut.String()).To(ContainSubstring("Cats API / Get all cats - OK"))
+				Expect(mockStdOut.String()).To(HaveSuffix("1 passed, 0 failed, 0 skipped\n"))
+			})
+
+			Context("when the documentation is redacted and truncated", func() {
+
+				BeforeEach(func() {
+					verifyCommand.Config.Redact = true
+					verifyCommand.Config.MaxBodySize = 4
+				})
+
+				It("should build the collection with the original values", func() {
+					options := mockCollectionBuilder.Calls[0].Arguments.Get(1).(postman.BuilderOptions)
+					Expect(options.Documentation).To(BeFalse())
+					Expect(mockStdOut.String()).To(HaveSuffix("1 passed, 0 failed, 0 skipped\n"))
+				})
+
+			})
+
+			Context("when the live response does not match", func() {
+
+				BeforeEach(func() {
+					statusCode = 500
+				})
+
+				It("should return an error", func() {
+					Expect(returnedError).NotTo(BeNil())
+					Expect(returnedError.Error()).To(Equal("1 of 1 contract tests failed"))
+				})
+
+			})
+
+			Context("when a JUnit report is requested", func() {
+
+				var dir string
+
+				BeforeEach(func() {
+					dir = must(ioutil.TempDir("", "postmanerator-verify")).(string)
+					verifyCommand.Config.JUnitFile = filepath.Join(dir, "report.xml")
+				})
+
+				AfterEach(func() {
+					os.RemoveAll(dir)
+				})
+
+				It("should write it", func() {
+					Expect(readFileContents(verifyCommand.Config.JUnitFile)).To(ContainSubstring(
+						`<testcase classname="Cats API" name="Get all cats - OK"`))
+				})
+
+			})
+
+		})
+
+		Context("when no collection is provided", func() {
+
+			BeforeEach(func() {
+				verifyCommand.Config.CollectionFile = ""
+			})
+
+			It("should return an error", func() {
+				Expect(returnedError).NotTo(BeNil())
+				Expect(returnedError.Error()).To(Equal("You must provide a collection using the -collection flag"))
+			})
+
+		})
+
+		Context("when parsing the collection file fails", func() {
+
+			BeforeEach(func() {
+				mockCollectionBuilder.On("FromFile", any, any).Return(postman.Collection{}, errors.New("something bad happened!"))
+			})
+
+			It("should return an error", func() {
+				Expect(returnedError).NotTo(BeNil())
+				Expect(returnedError.Error()).To(Equal("Failed to parse collection file: something bad happened!"))
+			})
+
+		})
+
+	})
+
+})
@@ -0,0 +1,152 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/aubm/postmanerator/configuration"
+	"github.com/aubm/postmanerator/themes"
+	"github.com/howeyc/fsnotify"
+)
+
+// watchDebounceDelay is how long the watcher waits for the file system to settle down,
+// editors and git checkouts usually produce bursts of events for a single change.
+const watchDebounceDelay = 200 * time.Millisecond
+
+// fileWatcher watches whole directory trees, and single files through their parent directory
+// so that files replaced by editors or re-exported by Postman keep being watched.
+type fileWatcher struct {
+	watcher     *fsnotify.Watcher
+	directories map[string]bool
+	files       map[string]bool
+	watched     map[string]bool
+}
+
+func newFileWatcher() (*fileWatcher, error) {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil, fmt.Errorf("Failed to create file watcher: %v", err)
+	}
+	return &fileWatcher{
+		watcher:     watcher,
+		directories: make(map[string]bool),
+		files:       make(map[string]bool),
+		watched:     make(map[string]bool),
+	}, nil
+}
+
+// watchSources watches everything the generated documentation is made of.
+func watchSources(watcher *fileWatcher, config *configuration.Configuration, theme *themes.Theme) error {
+	for t := theme; t != nil; t = t.Parent {
+		if err := watcher.WatchDirectory(t.Path); err != nil {
+			return err
+		}
+	}
+	if err := watcher.WatchFile(config.CollectionFile); err != nil {
+		return err
+	}
+	return watcher.WatchFile(config.EnvironmentFile)
+}
+
+func (w *fileWatcher) WatchDirectory(directory string) error {
+	directory = absolutePath(directory)
+	w.directories[directory] = true
+	return w.watchTree(directory)
+}
+
+func (w *fileWatcher) WatchFile(file string) error {
+	if file == "" {
+		return nil
+	}
+	file = absolutePath(file)
+	w.files[file] = true
+	return w.watch(filepath.Dir(file))
+}
+
+func (w *fileWatcher) watchTree(directory string) error {
+	return filepath.Walk(directory, func(file string, info os.FileInfo, err error) error {
+		if err != nil {
+			return fmt.Errorf("Failed to watch %v: %v", file, err)
+		}
+		if !info.IsDir() {
+			return nil
+		}
+		return w.watch(file)
+	})
+}
+
+func (w *fileWatcher) watch(directory string) error {
+	if w.watched[directory] {
+		return nil
+	}
+	if err := w.watcher.Watch(directory); err != nil {
+		return fmt.Errorf("Failed to watch %v: %v", directory, err)
+	}
+	w.watched[directory] = true
+	return nil
+}
+
+// Run executes the action once the relevant changes settled down, until the watcher is closed.
+func (w *fileWatcher) Run(action func(), onError func(error)) {
+	var debounce <-chan time.Time
+	for {
+		select {
+		case ev, ok := <-w.watcher.Event:
+			if !ok {
+				return
+			}
+			if ev.IsAttrib() || !w.isRelevant(ev.Name) {
+				continue
+			}
+			if ev.IsCreate() {
+				w.watchCreatedDirectory(ev.Name, onError)
+			}
+			debounce = time.After(watchDebounceDelay)
+		case <-debounce:
+			debounce = nil
+			action()
+		case err, ok := <-w.watcher.Error:
+			if !ok {
+				return
+			}
+			onError(err)
+		}
+	}
+}
+
+func (w *fileWatcher) watchCreatedDirectory(file string, onError func(error)) {
+	if info, err := os.Stat(file); err != nil || !info.IsDir() || !w.isInWatchedDirectory(file) {
+		return
+	}
+	if err := w.watchTree(file); err != nil {
+		onError(err)
+	}
+}
+
+func (w *fileWatcher) isRelevant(file string) bool {
+	return w.files[absolutePath(file)] || w.isInWatchedDirectory(file)
+}
+
+func (w *fileWatcher) isInWatchedDirectory(file string) bool {
+	file = absolutePath(file)
+	for directory := range w.directories {
+		if file == directory || strings.HasPrefix(file, directory+string(filepath.Separator)) {
+			return true
+		}
+	}
+	return false
+}
+
+func (w *fileWatcher) Close() error {
+	return w.watcher.Close()
+}
+
+func absolutePath(file string) string {
+	if abs, err := filepath.Abs(file); err == nil {
+		return abs
+	}
+	return filepath.Clean(file)
+}
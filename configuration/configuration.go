@@ -12,16 +12,41 @@ import (
 
 type Configuration struct {
 	Out                                        io.Writer
+	Err                                        io.Writer
 	ThemesRepository                           string
 	SleepTimeBetweenEachThemeDownloadInSeconds int
 	CollectionFile                             string
 	EnvironmentFile                            string
+	StructuresFile                             string
+	InferStructures                            bool
+	Include                                    StringsFlag
+	Exclude                                    StringsFlag
+	Tags                                       StringsFlag
+	Sort                                       string
+	Redact                                     bool
+	RedactPatterns                             RepeatedStringsFlag
+	MaxBodySize                                int
+	MaxArrayItems                              int
+	ConfigFile                                 string
 	UsedTheme                                  string
+	ThemeOptions                               RepeatedStringsFlag
 	OutputFile                                 string
+	OutputDirectory                            string
+	FingerprintAssets                          bool
 	Watch                                      bool
+	Port                                       int
+	BaseURL                                    string
+	ExactMatch                                 bool
+	JUnitFile                                  string
+	Listen                                     string
+	Upstream                                   string
+	RecordFile                                 string
 	ThemeLocalName                             string
 	IgnoredRequestHeaders                      StringsFlag
 	IgnoredResponseHeaders                     StringsFlag
+	AllowedRequestHeaders                      StringsFlag
+	AllowedResponseHeaders                     StringsFlag
+	Strict                                     bool
 	ThemesDirectory                            string
 	Args                                       []string
 }
@@ -37,6 +62,7 @@ var (
 	InitErr error
 	Config  = &Configuration{
 		Out:                                        os.Stdout,
+		Err:                                        os.Stderr,
 		ThemesRepository:                           defaultThemesRepository,
 		SleepTimeBetweenEachThemeDownloadInSeconds: 3,
 	}
@@ -48,18 +74,45 @@ Please consult the documentation here https://github.com/aubm/postmanerator and
 func Init() {
 	parseCommandFlags()
 	parseCommandArgs()
+	if InitErr = applyConfigFile(flag.CommandLine, Config.ConfigFile); InitErr != nil {
+		return
+	}
 	InitErr = parseThemesDir()
 }
 
 func parseCommandFlags() {
 	flag.StringVar(&Config.CollectionFile, "collection", "", "the postman exported collection JSON file")
 	flag.StringVar(&Config.EnvironmentFile, "environment", "", "the postman exported environment JSON file")
+	flag.StringVar(&Config.StructuresFile, "structures", "", "a YAML or JSON file defining API structures, in the native or in the JSON Schema format")
+	flag.BoolVar(&Config.InferStructures, "infer-structures", false, "infer API structures from the JSON bodies of the requests and of the saved responses")
+	flag.Var(&Config.Include, "include", "a comma separated list of glob patterns, only the matching folders and requests are documented")
+	flag.Var(&Config.Exclude, "exclude", "a comma separated list of glob patterns, the matching folders and requests are not documented")
+	flag.Var(&Config.Tags, "tags", "a comma separated list of tags, only the requests having one of them are documented, \"!tag\" excludes the requests having the tag")
+	flag.StringVar(&Config.Sort, "sort", "none", "the order of the requests: alpha, method, path or none to keep the order of the collection")
+	flag.BoolVar(&Config.Redact, "redact", false, "replace the secrets of the headers, URLs and bodies, such as authorization headers, tokens and JWTs")
+	flag.Var(&Config.RedactPatterns, "redact-pattern", "a regular expression whose matches are redacted, only the first group when there is one, can be repeated and implies -redact")
+	flag.IntVar(&Config.MaxBodySize, "max-body-size", 0, "truncate the response bodies longer than this number of bytes, 0 means no limit")
+	flag.IntVar(&Config.MaxArrayItems, "max-array-items", 0, "truncate the JSON arrays of the response bodies longer than this number of items, 0 means no limit")
+	flag.StringVar(&Config.ConfigFile, "config", "", "a YAML file whose keys are the names of the command line options")
 	flag.StringVar(&Config.UsedTheme, "theme", "default", "the theme to use")
+	flag.Var(&Config.ThemeOptions, "theme-option", "an option of the theme, as key=value, can be repeated")
 	flag.StringVar(&Config.OutputFile, "output", "", "the output file, default is stdout")
-	flag.BoolVar(&Config.Watch, "watch", false, "automatically regenerate the output when the theme changes")
+	flag.StringVar(&Config.OutputDirectory, "output-dir", "", "the output directory, generates one page per folder and per request")
+	flag.BoolVar(&Config.FingerprintAssets, "fingerprint-assets", false, "add a content hash to the names of the theme assets copied in the output directory")
+	flag.BoolVar(&Config.Watch, "watch", false, "automatically regenerate the output when the theme, the collection or the environment changes")
+	flag.IntVar(&Config.Port, "port", 0, "the port the server listens on, default is 8080 for the serve command and 3000 for the mock command")
+	flag.StringVar(&Config.BaseURL, "base-url", "", "the base URL of the server checked by the verify command")
+	flag.BoolVar(&Config.ExactMatch, "exact", false, "require the verify command to match the saved headers and bodies exactly, instead of their shape")
+	flag.StringVar(&Config.JUnitFile, "junit", "", "the JUnit XML report written by the verify and test commands")
+	flag.StringVar(&Config.Listen, "listen", ":9000", "the address the record command listens on")
+	flag.StringVar(&Config.Upstream, "upstream", "", "the URL of the server the record command forwards the requests to")
+	flag.StringVar(&Config.RecordFile, "out", "collection.json", "the collection file written by the record command")
 	flag.StringVar(&Config.ThemeLocalName, "theme-local-name", "", "the name of the local copy of the downloaded theme")
-	flag.Var(&Config.IgnoredResponseHeaders, "ignored-response-headers", "a comma separated list of ignored response headers")
-	flag.Var(&Config.IgnoredRequestHeaders, "ignored-request-headers", "a comma separated list of ignored request headers")
+	flag.Var(&Config.IgnoredResponseHeaders, "ignored-response-headers", "a comma separated list of ignored response headers, as case insensitive names, globs or /regular expressions/")
+	flag.Var(&Config.IgnoredRequestHeaders, "ignored-request-headers", "a comma separated list of ignored request headers, as case insensitive names, globs or /regular expressions/")
+	flag.Var(&Config.AllowedResponseHeaders, "allowed-response-headers", "a comma separated list of header patterns, only the matching response headers are documented")
+	flag.Var(&Config.AllowedRequestHeaders, "allowed-request-headers", "a comma separated list of header patterns, only the matching request headers are documented")
+	flag.BoolVar(&Config.Strict, "strict", false, "fail on any warning of the collection, such as a script that can not be evaluated, an example not matching its structure, a duplicate structure name or an invalid sunset date, instead of printing it")
 	flag.Parse()
 }
 
@@ -120,6 +173,11 @@ func checkOrCreateThemesDir(themesDir string) error {
 	return nil
 }
 
+// parseCommandArgs allows flags to be placed after the command, as in "postmanerator serve -port 8000".
 func parseCommandArgs() {
-	Config.Args = flag.Args()
+	Config.Args = make([]string, 0)
+	for args := flag.Args(); len(args) > 0; args = flag.Args() {
+		Config.Args = append(Config.Args, args[0])
+		flag.CommandLine.Parse(args[1:])
+	}
 }
@@ -0,0 +1,91 @@
+package configuration
+
+import (
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"sort"
+	"strings"
+
+	yaml "gopkg.in/yaml.v2"
+)
+
+// applyConfigFile sets the options found in a YAML config file, whose keys are the names of the command line options.
+// The options given on the command line take precedence over the ones of the file.
+func applyConfigFile(flags *flag.FlagSet, file string) error {
+	if file == "" {
+		return nil
+	}
+
+	contents, err := ioutil.ReadFile(file)
+	if err != nil {
+		return fmt.Errorf("Failed to read config file: %v", err)
+	}
+	options := map[string]interface{}{}
+	if err := yaml.Unmarshal(contents, &options); err != nil {
+		return fmt.Errorf("Failed to parse config file %v: %v", file, err)
+	}
+
+	setOnCommandLine := map[string]bool{}
+	flags.Visit(func(f *flag.Flag) {
+		setOnCommandLine[f.Name] = true
+	})
+
+	names := make([]string, 0, len(options))
+	for name := range options {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		if flags.Lookup(name) == nil || name == "config" {
+			return fmt.Errorf("Unknown option %v in config file %v", name, file)
+		}
+		if setOnCommandLine[name] {
+			continue
+		}
+		for _, value := range configValues(flags.Lookup(name), options[name]) {
+			if err := flags.Set(name, value); err != nil {
+				return fmt.Errorf("Invalid value for option %v in config file %v: %v", name, file, err)
+			}
+		}
+	}
+	return nil
+}
+
+// configValues returns the values to set for a config file option. A list sets a repeated option once per item, and
+// a mapping sets it once per "key=value" entry.
+func configValues(f *flag.Flag, value interface{}) []string {
+	if _, repeated := f.Value.(*RepeatedStringsFlag); !repeated {
+		return []string{configValue(value)}
+	}
+	switch v := value.(type) {
+	case []interface{}:
+		values := make([]string, 0, len(v))
+		for _, item := range v {
+			values = append(values, fmt.Sprint(item))
+		}
+		return values
+	case map[interface{}]interface{}:
+		values := make([]string, 0, len(v))
+		for key, item := range v {
+			values = append(values, fmt.Sprintf("%v=%v", key, item))
+		}
+		sort.Strings(values)
+		return values
+	}
+	return []string{configValue(value)}
+}
+
+// configValue formats a config file value as it would be written on the command line, lists being comma separated.
+func configValue(value interface{}) string {
+	list, ok := value.([]interface{})
+	if !ok {
+		return fmt.Sprint(value)
+	}
+	values := make([]string, 0, len(list))
+	for _, item := range list {
+		values = append(values, fmt.Sprint(item))
+	}
+	return strings.Join(values, ",")
+}
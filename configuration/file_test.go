@@ -0,0 +1,143 @@
+package configuration
+
+import (
+	"flag"
+	"io/ioutil"
+	"os"
+	"path"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	uuid "github.com/satori/go.uuid"
+)
+
+var _ = Describe("Config file", func() {
+
+	var (
+		flags         *flag.FlagSet
+		configFile    string
+		fileContent   string
+		args          []string
+		output        string
+		tags          StringsFlag
+		themeOptions  RepeatedStringsFlag
+		redact        bool
+		returnedError error
+	)
+
+	BeforeEach(func() {
+		flags = flag.NewFlagSet("postmanerator", flag.ContinueOnError)
+		tags, themeOptions, redact = StringsFlag{}, RepeatedStringsFlag{}, false
+		flags.String("config", "", "")
+		flags.StringVar(&output, "output", "", "")
+		flags.Var(&tags, "tags", "")
+		flags.Var(&themeOptions, "theme-option", "")
+		flags.BoolVar(&redact, "redact", false, "")
+		configFile = path.Join(os.TempDir(), uuid.NewV4().String()+".yml")
+		args = nil
+	})
+
+	JustBeforeEach(func() {
+		if err := ioutil.WriteFile(configFile, []byte(fileContent), 0666); err != nil {
+			panic(err)
+		}
+		if err := flags.Parse(args); err != nil {
+			panic(err)
+		}
+		returnedError = applyConfigFile(flags, configFile)
+	})
+
+	AfterEach(func() {
+		os.Remove(configFile)
+	})
+
+	Context("when the file sets options", func() {
+
+		BeforeEach(func() {
+			fileContent = "output: doc.html\nredact: true\ntags: [public, beta]\ntheme-option: [company=ACME, \"logo=a,b.png\"]\n"
+		})
+
+		It("should set them", func() {
+			Expect(returnedError).To(BeNil())
+			Expect(output).To(Equal("doc.html"))
+			Expect(redact).To(BeTrue())
+		})
+
+		It("should join the lists of the options split on commas", func() {
+			Expect(tags.Values).To(Equal([]string{"public", "beta"}))
+		})
+
+		It("should set the repeated options once per item", func() {
+			Expect(themeOptions.Values).To(Equal([]string{"company=ACME", "logo=a,b.png"}))
+		})
+
+	})
+
+	Context("when a repeated option is given as a mapping", func() {
+
+		BeforeEach(func() {
+			fileContent = "theme-option:\n  logo: logo.png\n  company: ACME\n"
+		})
+
+		It("should set it once per key=value entry", func() {
+			Expect(returnedError).To(BeNil())
+			Expect(themeOptions.Values).To(Equal([]string{"company=ACME", "logo=logo.png"}))
+		})
+
+	})
+
+	Context("when an option is also given on the command line", func() {
+
+		BeforeEach(func() {
+			fileContent = "output: doc.html\ntags: [public]\n"
+			args = []string{"-output", "api.html"}
+		})
+
+		It("should keep the value of the command line", func() {
+			Expect(returnedError).To(BeNil())
+			Expect(output).To(Equal("api.html"))
+			Expect(tags.Values).To(Equal([]string{"public"}))
+		})
+
+	})
+
+	Context("when the file sets the config option", func() {
+
+		BeforeEach(func() {
+			fileContent = "config: other.yml\n"
+		})
+
+		It("should return an error", func() {
+			Expect(returnedError).NotTo(BeNil())
+			Expect(returnedError.Error()).To(Equal("Unknown option config in config file " + configFile))
+		})
+
+	})
+
+	Context("when the file sets an unknown option", func() {
+
+		BeforeEach(func() {
+			fileContent = "colour: blue\n"
+		})
+
+		It("should return an error", func() {
+			Expect(returnedError).NotTo(BeNil())
+			Expect(returnedError.Error()).To(Equal("Unknown option colour in config file " + configFile))
+		})
+
+	})
+
+	Context("when an option has an invalid value", func() {
+
+		BeforeEach(func() {
+			fileContent = "redact: sometimes\n"
+		})
+
+		It("should return an error", func() {
+			Expect(returnedError).NotTo(BeNil())
+			Expect(returnedError.Error()).To(HavePrefix("Invalid value for option redact in config file " + configFile))
+		})
+
+	})
+
+})
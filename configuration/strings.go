@@ -19,3 +19,19 @@ func (sf *StringsFlag) Set(value string) error {
 	}
 	return nil
 }
+
+// RepeatedStringsFlag collects the values of a flag given several times, without splitting them on commas.
+type RepeatedStringsFlag struct {
+	Values []string
+}
+
+func (rf RepeatedStringsFlag) String() string {
+	return fmt.Sprint(rf.Values)
+}
+
+func (rf *RepeatedStringsFlag) Set(value string) error {
+	if value != "" {
+		rf.Values = append(rf.Values, value)
+	}
+	return nil
+}
@@ -0,0 +1,10 @@
+package configuration
+
+// DevVersion is the version of the builds that are not releases. Themes requiring a version of Postmanerator accept
+// it, since the features of a development build are unknown.
+const DevVersion = "0.0.0-dev"
+
+// Version is the version of Postmanerator, checked against the requirements of the themes. The release build in
+// .travis.yml sets it to the tag being released with -ldflags "-X github.com/aubm/postmanerator/configuration.Version=...",
+// other builds report DevVersion.
+var Version = DevVersion
@@ -22,10 +22,17 @@ var (
 	collectionBuilder    = &postman.CollectionBuilder{}
 	collectionV210Parser = &postman.CollectionV210Parser{}
 	environmentBuilder   = &postman.EnvironmentBuilder{}
+	collectionWriter     = &postman.CollectionV210Writer{}
 	defaultCommand       = &commands.Default{}
 	getThemeCommand      = &commands.GetTheme{}
 	deleteThemeCommand   = &commands.DeleteTheme{}
 	listThemesCommand    = &commands.ListThemes{}
+	serveCommand         = &commands.Serve{}
+	mockCommand          = &commands.Mock{}
+	verifyCommand        = &commands.Verify{}
+	recordCommand        = &commands.Record{}
+	testCommand          = &commands.Test{}
+	sunsetCommand        = &commands.Sunset{}
 	availableCommands    = []commands.Command{}
 )
 
@@ -36,7 +43,8 @@ func init() {
 func _init() error {
 	configuration.Init()
 	if err := inject.Populate(config, themeManager, defaultCommand, getThemeCommand, deleteThemeCommand,
-		listThemesCommand, gitAgent, themeRenderer, collectionBuilder, collectionV210Parser, environmentBuilder); err != nil {
+		listThemesCommand, serveCommand, mockCommand, verifyCommand, recordCommand, testCommand, sunsetCommand, gitAgent, themeRenderer, collectionBuilder, collectionV210Parser,
+		environmentBuilder, collectionWriter); err != nil {
 		return fmt.Errorf("app initialization failed: %v", err)
 	}
 	collectionBuilder.Parsers = append(collectionBuilder.Parsers, collectionV210Parser)
@@ -45,6 +53,12 @@ func _init() error {
 		getThemeCommand,
 		deleteThemeCommand,
 		listThemesCommand,
+		serveCommand,
+		mockCommand,
+		verifyCommand,
+		recordCommand,
+		testCommand,
+		sunsetCommand,
 	)
 	return nil
 }
@@ -70,6 +84,18 @@ func evaluateUserCommand() string {
 	}
 
 	switch config.Args[0] {
+	case "serve":
+		return commands.CmdServe
+	case "mock":
+		return commands.CmdMock
+	case "verify":
+		return commands.CmdVerify
+	case "record":
+		return commands.CmdRecord
+	case "test":
+		return commands.CmdTest
+	case "sunset":
+		return commands.CmdSunset
 	case "themes":
 		if len(config.Args) < 2 {
 			return commands.CmdThemesList
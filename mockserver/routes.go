@@ -0,0 +1,68 @@
+package mockserver
+
+import (
+	"strings"
+
+	"github.com/aubm/postmanerator/postman"
+)
+
+type route struct {
+	method   string
+	segments []string
+	request  postman.Request
+}
+
+func newRoutes(collection postman.Collection) []route {
+	routes := make([]route, 0)
+	folder := postman.Folder{Requests: collection.Requests, Folders: collection.Folders}
+	return appendFolderRoutes(routes, folder)
+}
+
+func appendFolderRoutes(routes []route, folder postman.Folder) []route {
+	for _, request := range folder.Requests {
+		routes = append(routes, route{
+			method:   strings.ToUpper(request.Method),
+			segments: pathSegments(request.Path()),
+			request:  request,
+		})
+	}
+	for _, subFolder := range folder.Folders {
+		routes = appendFolderRoutes(routes, subFolder)
+	}
+	return routes
+}
+
+// match returns how many literal segments of the route match the path, or -1 if it does not match at all.
+func (r route) match(method string, segments []string) int {
+	if r.method != strings.ToUpper(method) || len(r.segments) != len(segments) {
+		return -1
+	}
+	score := 0
+	for i, segment := range r.segments {
+		if isWildcard(segment) {
+			continue
+		}
+		if segment != segments[i] {
+			return -1
+		}
+		score++
+	}
+	return score
+}
+
+// pathSegments splits a path into its non empty segments.
+func pathSegments(path string) []string {
+	segments := make([]string, 0)
+	for _, segment := range strings.Split(path, "/") {
+		if segment != "" {
+			segments = append(segments, segment)
+		}
+	}
+	return segments
+}
+
+// isWildcard tells whether a path segment is a path variable such as ":id" or "{{id}}".
+func isWildcard(segment string) bool {
+	return strings.HasPrefix(segment, ":") ||
+		(strings.HasPrefix(segment, "{{") && strings.HasSuffix(segment, "}}"))
+}
@@ -0,0 +1,171 @@
+package mockserver
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/aubm/postmanerator/postman"
+)
+
+const (
+	ResponseNameHeader = "x-mock-response-name"
+	ResponseCodeHeader = "x-mock-response-code"
+)
+
+// skippedHeaders are recomputed by the server, saved values would not match the body sent.
+var skippedHeaders = map[string]bool{
+	"content-length":    true,
+	"content-encoding":  true,
+	"transfer-encoding": true,
+	"connection":        true,
+}
+
+// Server replies to incoming requests with the responses saved in a collection.
+type Server struct {
+	routes []route
+	// OnResponse is called for each served request, with a nil response when nothing matched.
+	OnResponse func(r *http.Request, request *postman.Request, response *postman.Response)
+}
+
+func NewServer(collection postman.Collection) *Server {
+	return &Server{routes: newRoutes(collection)}
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	request := s.findRequest(r)
+	if request == nil {
+		s.notify(r, nil, nil)
+		http.Error(w, fmt.Sprintf("No request found for %v %v", r.Method, r.URL.Path), http.StatusNotFound)
+		return
+	}
+
+	response, err := s.findResponse(r, *request)
+	if err != nil {
+		s.notify(r, request, nil)
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+
+	s.notify(r, request, response)
+	s.writeResponse(w, *response)
+}
+
+func (s *Server) notify(r *http.Request, request *postman.Request, response *postman.Response) {
+	if s.OnResponse != nil {
+		s.OnResponse(r, request, response)
+	}
+}
+
+func (s *Server) findRequest(r *http.Request) *postman.Request {
+	segments := pathSegments(r.URL.Path)
+	var found *postman.Request
+	bestScore := -1
+	for i, route := range s.routes {
+		if score := route.match(r.Method, segments); score > bestScore {
+			found = &s.routes[i].request
+			bestScore = score
+		}
+	}
+	return found
+}
+
+func (s *Server) findResponse(r *http.Request, request postman.Request) (*postman.Response, error) {
+	if len(request.Responses) == 0 {
+		return nil, fmt.Errorf("No saved response for request %v", request.Name)
+	}
+
+	if name := r.Header.Get(ResponseNameHeader); name != "" {
+		for i, res := range request.Responses {
+			if res.Name == name {
+				return &request.Responses[i], nil
+			}
+		}
+		return nil, fmt.Errorf("No saved response named %v for request %v", name, request.Name)
+	}
+
+	if code := r.Header.Get(ResponseCodeHeader); code != "" {
+		for i, res := range request.Responses {
+			if strconv.Itoa(res.StatusCode) == code {
+				return &request.Responses[i], nil
+			}
+		}
+		return nil, fmt.Errorf("No saved response with status code %v for request %v", code, request.Name)
+	}
+
+	if response := s.findResponseByBody(r, request); response != nil {
+		return response, nil
+	}
+
+	for i, res := range request.Responses {
+		if res.StatusCode >= 200 && res.StatusCode < 300 {
+			return &request.Responses[i], nil
+		}
+	}
+	return &request.Responses[0], nil
+}
+
+// findResponseByBody returns the response whose original request has the body closest to the incoming one.
+func (s *Server) findResponseByBody(r *http.Request, request postman.Request) *postman.Response {
+	if r.Body == nil {
+		return nil
+	}
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil || len(body) == 0 {
+		return nil
+	}
+
+	var found *postman.Response
+	bestScore := 0
+	for i, res := range request.Responses {
+		if res.OriginalRequest == nil {
+			continue
+		}
+		if score := bodySimilarity(string(body), res.OriginalRequest.PayloadRaw); score > bestScore {
+			found = &request.Responses[i]
+			bestScore = score
+		}
+	}
+	return found
+}
+
+// bodySimilarity counts the top level JSON properties both bodies have in common,
+// bodies that are not JSON objects only match when they are identical.
+func bodySimilarity(a, b string) int {
+	if strings.TrimSpace(a) == strings.TrimSpace(b) {
+		return len(a) + 1
+	}
+
+	var objectA, objectB map[string]interface{}
+	if json.Unmarshal([]byte(a), &objectA) != nil || json.Unmarshal([]byte(b), &objectB) != nil {
+		return 0
+	}
+
+	score := 0
+	for key, valueA := range objectA {
+		if valueB, ok := objectB[key]; ok && reflect.DeepEqual(valueA, valueB) {
+			score++
+		}
+	}
+	return score
+}
+
+func (s *Server) writeResponse(w http.ResponseWriter, response postman.Response) {
+	for _, header := range response.Headers {
+		if skippedHeaders[strings.ToLower(header.Key)] {
+			continue
+		}
+		w.Header().Add(header.Key, fmt.Sprint(header.Value))
+	}
+
+	statusCode := response.StatusCode
+	if statusCode == 0 {
+		statusCode = http.StatusOK
+	}
+	w.WriteHeader(statusCode)
+	fmt.Fprint(w, response.CompleteBody())
+}
@@ -0,0 +1,221 @@
+package mockserver_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+
+	. "github.com/aubm/postmanerator/mockserver"
+	"github.com/aubm/postmanerator/postman"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var exampleCollection = postman.Collection{
+	Requests: []postman.Request{
+		{
+			Name:   "Get all cats",
+			Method: "GET",
+			URL:    "{{url}}/cats?page=1",
+			Responses: []postman.Response{
+				{Name: "OK", StatusCode: 200, Body: `[{"name":"Barney"}]`, Headers: []postman.KeyValuePair{
+					{Key: "Content-Type", Value: "application/json"},
+					{Key: "Content-Length", Value: "42"},
+				}},
+			},
+		},
+	},
+	Folders: []postman.Folder{
+		{
+			Name: "Dogs",
+			Requests: []postman.Request{
+				{
+					Name:   "Get one dog",
+					Method: "GET",
+					URL:    "https://my-api/dogs/:id",
+					Responses: []postman.Response{
+						{Name: "Not found", StatusCode: 404, Body: `{"error":"not found"}`},
+						{Name: "OK", StatusCode: 200, Body: `{"name":"Sam"}`, Headers: []postman.KeyValuePair{
+							{Key: "X-Dog", Value: "sam"},
+						}},
+					},
+				},
+				{
+					Name:   "Get the best dog",
+					Method: "GET",
+					URL:    "https://my-api/dogs/best",
+					Responses: []postman.Response{
+						{Name: "OK", StatusCode: 200, Body: `{"name":"Rex"}`},
+					},
+				},
+				{
+					Name:   "Create a dog",
+					Method: "POST",
+					URL:    "https://my-api/dogs",
+					Responses: []postman.Response{
+						{Name: "Created", StatusCode: 201, Body: `{"name":"Sam"}`, OriginalRequest: &postman.Request{
+							PayloadRaw: `{"name":"Sam","color":"brown"}`,
+						}},
+						{Name: "Invalid", StatusCode: 400, Body: `{"error":"invalid"}`, OriginalRequest: &postman.Request{
+							PayloadRaw: `{"name":"","color":"brown"}`,
+						}},
+					},
+				},
+				{
+					Name:   "Delete a dog",
+					Method: "DELETE",
+					URL:    "https://my-api/dogs/:id",
+				},
+			},
+		},
+	},
+}
+
+var _ = Describe("Server", func() {
+
+	var (
+		server        *Server
+		request       *http.Request
+		servedRequest *postman.Request
+		response      *httptest.ResponseRecorder
+	)
+
+	BeforeEach(func() {
+		server = NewServer(exampleCollection)
+		servedRequest = nil
+		server.OnResponse = func(r *http.Request, req *postman.Request, res *postman.Response) {
+			servedRequest = req
+		}
+	})
+
+	JustBeforeEach(func() {
+		response = httptest.NewRecorder()
+		server.ServeHTTP(response, request)
+	})
+
+	Context("when the request matches a collection request", func() {
+
+		BeforeEach(func() {
+			request = httptest.NewRequest(http.MethodGet, "/cats?page=2", nil)
+		})
+
+		It("should reply with the saved response", func() {
+			Expect(response.Code).To(Equal(200))
+			Expect(response.Body.String()).To(Equal(`[{"name":"Barney"}]`))
+			Expect(response.Header().Get("Content-Type")).To(Equal("application/json"))
+		})
+
+		It("should not reply with the saved content length", func() {
+			Expect(response.Header().Get("Content-Length")).To(BeEmpty())
+		})
+
+	})
+
+	Context("when the request matches a path variable", func() {
+
+		BeforeEach(func() {
+			request = httptest.NewRequest(http.MethodGet, "/dogs/42", nil)
+		})
+
+		It("should reply with the first successful saved response", func() {
+			Expect(response.Code).To(Equal(200))
+			Expect(response.Body.String()).To(Equal(`{"name":"Sam"}`))
+			Expect(response.Header().Get("X-Dog")).To(Equal("sam"))
+		})
+
+	})
+
+	Context("when the request matches both a path variable and a literal path", func() {
+
+		BeforeEach(func() {
+			request = httptest.NewRequest(http.MethodGet, "/dogs/best", nil)
+		})
+
+		It("should prefer the literal path", func() {
+			Expect(servedRequest.Name).To(Equal("Get the best dog"))
+			Expect(response.Body.String()).To(Equal(`{"name":"Rex"}`))
+		})
+
+	})
+
+	Context("when a response is selected by name", func() {
+
+		BeforeEach(func() {
+			request = httptest.NewRequest(http.MethodGet, "/dogs/42", nil)
+			request.Header.Set("x-mock-response-name", "Not found")
+		})
+
+		It("should reply with that response", func() {
+			Expect(response.Code).To(Equal(404))
+			Expect(response.Body.String()).To(Equal(`{"error":"not found"}`))
+		})
+
+	})
+
+	Context("when a response is selected by an unknown name", func() {
+
+		BeforeEach(func() {
+			request = httptest.NewRequest(http.MethodGet, "/dogs/42", nil)
+			request.Header.Set("x-mock-response-name", "Gone")
+		})
+
+		It("should reply with a not found error", func() {
+			Expect(response.Code).To(Equal(404))
+			Expect(response.Body.String()).To(ContainSubstring("No saved response named Gone for request Get one dog"))
+		})
+
+	})
+
+	Context("when a response is selected by status code", func() {
+
+		BeforeEach(func() {
+			request = httptest.NewRequest(http.MethodGet, "/dogs/42", nil)
+			request.Header.Set("x-mock-response-code", "404")
+		})
+
+		It("should reply with that response", func() {
+			Expect(response.Body.String()).To(Equal(`{"error":"not found"}`))
+		})
+
+	})
+
+	Context("when a request body is sent", func() {
+
+		BeforeEach(func() {
+			request = httptest.NewRequest(http.MethodPost, "/dogs", strings.NewReader(`{"name":"","color":"brown"}`))
+		})
+
+		It("should reply with the response whose original request has the closest body", func() {
+			Expect(response.Code).To(Equal(400))
+			Expect(response.Body.String()).To(Equal(`{"error":"invalid"}`))
+		})
+
+	})
+
+	Context("when the request has no saved response", func() {
+
+		BeforeEach(func() {
+			request = httptest.NewRequest(http.MethodDelete, "/dogs/42", nil)
+		})
+
+		It("should reply with a not found error", func() {
+			Expect(response.Code).To(Equal(404))
+			Expect(response.Body.String()).To(ContainSubstring("No saved response for request Delete a dog"))
+		})
+
+	})
+
+	Context("when the request does not match anything", func() {
+
+		BeforeEach(func() {
+			request = httptest.NewRequest(http.MethodPut, "/dogs/42", nil)
+		})
+
+		It("should reply with a not found error", func() {
+			Expect(response.Code).To(Equal(404))
+			Expect(servedRequest).To(BeNil())
+		})
+
+	})
+
+})
@@ -0,0 +1,196 @@
+package postman
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// annotationPattern matches the "@body Name" and "@returns [code] Name" lines of a request description.
+var annotationPattern = regexp.MustCompile(`(?m)^[ \t]*@(body|returns)[ \t]+(?:([1-5]\d\d)[ \t]+)?(\S.*?)[ \t]*\r?(?:\n|$)`)
+
+// bodyAnnotations holds the structures a request description refers to.
+type bodyAnnotations struct {
+	body string
+	// returns holds the structures of the responses by status code, 0 standing for the successful responses.
+	returns map[int]string
+}
+
+// extractBodyAnnotations returns a description without its annotation lines, and the annotations it contains.
+func extractBodyAnnotations(description string) (string, bodyAnnotations) {
+	annotations := bodyAnnotations{returns: map[int]string{}}
+	matches := annotationPattern.FindAllStringSubmatch(description, -1)
+	if len(matches) == 0 {
+		return description, annotations
+	}
+	for _, match := range matches {
+		if match[1] == "body" {
+			annotations.body = match[3]
+			continue
+		}
+		code, _ := strconv.Atoi(match[2])
+		annotations.returns[code] = match[3]
+	}
+	return strings.TrimSpace(annotationPattern.ReplaceAllString(description, "")), annotations
+}
+
+func (a bodyAnnotations) responseStructure(statusCode int) string {
+	if name, ok := a.returns[statusCode]; ok {
+		return name
+	}
+	if statusCode == 0 || (statusCode >= 200 && statusCode < 300) {
+		return a.returns[0]
+	}
+	return ""
+}
+
+// linkBodyStructures sets the structures of the request and response bodies, as annotated in the request descriptions.
+// Without annotations, the bodies are linked to the inferred structures when the inference is enabled.
+func (c *CollectionBuilder) linkBodyStructures(col *Collection, options BuilderOptions) {
+	owners := inferenceOwners(col)
+	walkRequests(col, func(_ []*Folder, request *Request) {
+		c.linkRequestBodyStructures(col, owners[request], request, options)
+	})
+}
+
+func (c *CollectionBuilder) linkRequestBodyStructures(col *Collection, owner string, request *Request, options BuilderOptions) {
+	var annotations bodyAnnotations
+	request.Description, annotations = extractBodyAnnotations(request.Description)
+
+	bodyName := annotations.body
+	if bodyName == "" && options.InferStructures {
+		bodyName = inferredRequestStructureName(owner)
+	}
+	request.BodyStructure = c.findBodyStructure(col, request.Name, bodyName, annotations.body != "")
+	if request.BodyStructure != nil {
+		c.checkBody(col, fmt.Sprintf("%v, request body", request.Name), *request.BodyStructure, request.PayloadRaw)
+	}
+
+	for i := range request.Responses {
+		response := &request.Responses[i]
+		responseName := annotations.responseStructure(response.StatusCode)
+		annotated := responseName != ""
+		if !annotated && options.InferStructures {
+			responseName = inferredResponseStructureName(owner, response.StatusCode)
+		}
+		response.BodyStructure = c.findBodyStructure(col, request.Name, responseName, annotated)
+		if response.BodyStructure != nil {
+			c.checkBody(col, fmt.Sprintf("%v, response %q", request.Name, response.Name), *response.BodyStructure, response.Body)
+		}
+	}
+}
+
+// findBodyStructure returns the structure with the given name. Unknown structures are reported when they
+// were annotated, inferred structures being missing whenever the examples are not JSON objects.
+func (c *CollectionBuilder) findBodyStructure(col *Collection, requestName, name string, annotated bool) *StructureDefinition {
+	if name == "" {
+		return nil
+	}
+	structure := col.FindStructure(name)
+	if structure == nil && annotated {
+		col.Warnings = append(col.Warnings, fmt.Sprintf("%v: unknown structure %v", requestName, name))
+	}
+	return structure
+}
+
+// checkBody reports the differences between a JSON body and the structure it is annotated with.
+// Bodies that are not JSON, such as the ones using environment variables, are not checked.
+func (c *CollectionBuilder) checkBody(col *Collection, owner string, structure StructureDefinition, body string) {
+	var value interface{}
+	if err := json.Unmarshal([]byte(body), &value); err != nil {
+		return
+	}
+	items, ok := value.([]interface{})
+	if !ok {
+		items = []interface{}{value}
+	}
+	for _, item := range items {
+		for _, mismatch := range checkFields(col, structure.Fields, item, "") {
+			col.Warnings = append(col.Warnings, fmt.Sprintf("%v: body does not match %v: %v", owner, structure.Name, mismatch))
+		}
+	}
+}
+
+func checkFields(col *Collection, fields []StructureFieldDefinition, value interface{}, path string) []string {
+	obj, ok := value.(map[string]interface{})
+	if !ok {
+		return []string{fmt.Sprintf("expected an object%v, got %v", atPath(path), jsonKind(value))}
+	}
+	mismatches := make([]string, 0)
+	for _, field := range fields {
+		fieldPath := field.Name
+		if path != "" {
+			fieldPath = path + "." + field.Name
+		}
+		fieldValue, ok := obj[field.Name]
+		if !ok {
+			if field.Required {
+				mismatches = append(mismatches, fmt.Sprintf("missing required field %v", fieldPath))
+			}
+			continue
+		}
+		if fieldValue == nil {
+			continue
+		}
+		if expected, ok := expectedKind(col, field); ok && expected != jsonKind(fieldValue) && !(expected == "number" && jsonKind(fieldValue) == "integer") {
+			mismatches = append(mismatches, fmt.Sprintf("expected %v%v, got %v", expected, atPath(fieldPath), jsonKind(fieldValue)))
+			continue
+		}
+		if field.Fields != nil {
+			mismatches = append(mismatches, checkFields(col, field.Fields, fieldValue, fieldPath)...)
+		}
+	}
+	return mismatches
+}
+
+// expectedKind returns the JSON kind of the values of a field, when its type is a well known one.
+func expectedKind(col *Collection, field StructureFieldDefinition) (string, bool) {
+	switch strings.ToLower(field.Type) {
+	case "string":
+		return "string", true
+	case "int", "integer", "long":
+		return "integer", true
+	case "number", "float", "double":
+		return "number", true
+	case "bool", "boolean":
+		return "boolean", true
+	case "object":
+		return "object", true
+	case "array":
+		return "array", true
+	}
+	if col.FindStructure(field.Type) != nil {
+		return "object", true
+	}
+	return "", false
+}
+
+func jsonKind(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return "null"
+	case string:
+		return "string"
+	case bool:
+		return "boolean"
+	case float64:
+		if v == float64(int64(v)) {
+			return "integer"
+		}
+		return "number"
+	case []interface{}:
+		return "array"
+	case map[string]interface{}:
+		return "object"
+	}
+	return fmt.Sprintf("%T", value)
+}
+
+func atPath(path string) string {
+	if path == "" {
+		return ""
+	}
+	return " at " + path
+}
@@ -0,0 +1,141 @@
+package postman
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestExtractBodyAnnotations(t *testing.T) {
+	// Given
+	description := "Create a new cat.\n@body Cat\n@returns Cat\n  @returns 404 Not found error\n\nThe cat is saved."
+
+	// When
+	stripped, annotations := extractBodyAnnotations(description)
+
+	// Then
+	if stripped != "Create a new cat.\n\nThe cat is saved." {
+		t.Errorf("Annotations were not removed from the description, got %q", stripped)
+	}
+	if annotations.body != "Cat" {
+		t.Errorf("Expected the body structure to be Cat, got %q", annotations.body)
+	}
+	expectedReturns := map[int]string{0: "Cat", 404: "Not found error"}
+	if !reflect.DeepEqual(annotations.returns, expectedReturns) {
+		t.Errorf("Expected the response structures %v, got %v", expectedReturns, annotations.returns)
+	}
+	for code, expected := range map[int]string{201: "Cat", 404: "Not found error", 500: ""} {
+		if name := annotations.responseStructure(code); name != expected {
+			t.Errorf("Expected the structure of %d responses to be %q, got %q", code, expected, name)
+		}
+	}
+}
+
+func TestLinkBodyStructures(t *testing.T) {
+	// Given
+	builder := &CollectionBuilder{}
+	cat := StructureDefinition{Name: "Cat", Fields: []StructureFieldDefinition{
+		{Name: "id", Type: "int", Required: true},
+		{Name: "name", Type: "string", Required: true},
+		{Name: "owner", Type: "Person"},
+		{Name: "address", Type: "object", Fields: []StructureFieldDefinition{{Name: "city", Type: "string", Required: true}}},
+	}}
+	person := StructureDefinition{Name: "Person", Fields: []StructureFieldDefinition{{Name: "name", Type: "string"}}}
+	col := Collection{
+		Structures: []StructureDefinition{cat, person},
+		Folders: []Folder{{Name: "Cats", Requests: []Request{{
+			Name:        "Create a cat",
+			Description: "@body Cat\n@returns Cat\n@returns 400 Error",
+			PayloadRaw:  `{"id": 1, "name": "Felix"}`,
+			Responses: []Response{
+				{Name: "Created", StatusCode: 201, Body: `{"id": 1, "name": "Felix", "owner": {"name": "Jon"}}`},
+				{Name: "Invalid", StatusCode: 201, Body: `{"id": "1", "owner": "Jon", "address": {}}`},
+				{Name: "Bad request", StatusCode: 400, Body: `{"error": "invalid"}`},
+			},
+		}}}},
+	}
+
+	// When
+	builder.linkBodyStructures(&col, BuilderOptions{})
+
+	// Then
+	request := col.Folders[0].Requests[0]
+	if request.Description != "" {
+		t.Errorf("Annotations were not removed from the description, got %q", request.Description)
+	}
+	if request.BodyStructure == nil || request.BodyStructure.Name != "Cat" {
+		t.Errorf("Expected the request body to be linked to Cat, got %v", request.BodyStructure)
+	}
+	if s := request.Responses[0].BodyStructure; s == nil || s.Name != "Cat" {
+		t.Errorf("Expected the successful response to be linked to Cat, got %v", s)
+	}
+	if s := request.Responses[2].BodyStructure; s != nil {
+		t.Errorf("Expected the unknown structure not to be linked, got %v", s)
+	}
+	expectedWarnings := []string{
+		`Create a cat, response "Invalid": body does not match Cat: expected integer at id, got string`,
+		`Create a cat, response "Invalid": body does not match Cat: missing required field name`,
+		`Create a cat, response "Invalid": body does not match Cat: expected object at owner, got string`,
+		`Create a cat, response "Invalid": body does not match Cat: missing required field address.city`,
+		"Create a cat: unknown structure Error",
+	}
+	if !reflect.DeepEqual(col.Warnings, expectedWarnings) {
+		t.Errorf("Expected warnings %q, got %q", expectedWarnings, col.Warnings)
+	}
+}
+
+func TestLinkInferredBodyStructures(t *testing.T) {
+	// Given
+	builder := &CollectionBuilder{}
+	col := Collection{Requests: []Request{{
+		Name: "Get a cat",
+		Responses: []Response{
+			{StatusCode: 200, Body: `{"id": 1}`},
+			{StatusCode: 404, Body: `Not found`},
+		},
+	}}}
+	options := BuilderOptions{InferStructures: true}
+	builder.inferStructures(&col)
+
+	// When
+	builder.linkBodyStructures(&col, options)
+
+	// Then
+	request := col.Requests[0]
+	if request.BodyStructure != nil {
+		t.Errorf("Expected no request body structure, got %v", request.BodyStructure)
+	}
+	if s := request.Responses[0].BodyStructure; s == nil || s.Name != "Get a cat (200 response)" {
+		t.Errorf("Expected the response to be linked to the inferred structure, got %v", s)
+	}
+	if s := request.Responses[1].BodyStructure; s != nil {
+		t.Errorf("Expected no structure for a response that is not JSON, got %v", s)
+	}
+	if len(col.Warnings) != 0 {
+		t.Errorf("Unexpected warnings: %q", col.Warnings)
+	}
+}
+
+func TestLinkInferredBodyStructuresOfRequestsWithTheSameName(t *testing.T) {
+	// Given
+	builder := &CollectionBuilder{}
+	col := Collection{Folders: []Folder{
+		{Name: "Cats", Requests: []Request{{Name: "List", Responses: []Response{{StatusCode: 200, Body: `[{"name": "Felix"}]`}}}}},
+		{Name: "Dogs", Requests: []Request{{Name: "List", Responses: []Response{{StatusCode: 200, Body: `[{"breed": "Husky"}]`}}}}},
+	}}
+	options := BuilderOptions{InferStructures: true}
+	builder.inferStructures(&col)
+
+	// When
+	builder.linkBodyStructures(&col, options)
+
+	// Then
+	if s := col.Folders[0].Requests[0].Responses[0].BodyStructure; s == nil || s.Name != "Cats/List (200 response)" {
+		t.Errorf("Expected the cats to be linked to their own structure, got %v", s)
+	}
+	if s := col.Folders[1].Requests[0].Responses[0].BodyStructure; s == nil || s.Name != "Dogs/List (200 response)" {
+		t.Errorf("Expected the dogs to be linked to their own structure, got %v", s)
+	}
+	if len(col.Warnings) != 0 {
+		t.Errorf("Unexpected warnings: %q", col.Warnings)
+	}
+}
@@ -0,0 +1,125 @@
+package postman
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+)
+
+const stringLiteralPattern = `("(?:[^"\\\n]|\\.)*"|'(?:[^'\\\n]|\\.)*'|` + "`[^`]*`" + `)`
+
+var (
+	pmTestPattern      = regexp.MustCompile(`\bpm\.test\s*\(\s*` + stringLiteralPattern)
+	legacyTestsPattern = regexp.MustCompile(`\btests\s*\[\s*` + stringLiteralPattern + `\s*\]\s*=[^=]`)
+)
+
+// extractAssertions sets the assertions of each request, including the ones inherited from its folders and the collection.
+func (c *CollectionBuilder) extractAssertions(col *Collection) {
+	walkRequests(col, func(path []*Folder, request *Request) {
+		tests := col.Tests
+		for _, folder := range path {
+			tests = joinScripts(tests, folder.Tests)
+		}
+		request.Assertions = extractAssertions(joinScripts(tests, request.Tests))
+	})
+}
+
+func joinScripts(first, second string) string {
+	if first == "" || second == "" {
+		return first + second
+	}
+	return first + "\n" + second
+}
+
+// extractAssertions returns the names of the pm.test calls and of the legacy tests entries of a script,
+// in order of appearance and without duplicates. Commented out tests are ignored.
+func extractAssertions(script string) []string {
+	script = stripComments(script)
+
+	type match struct {
+		index int
+		name  string
+	}
+	matches := make([]match, 0)
+	for _, pattern := range []*regexp.Regexp{pmTestPattern, legacyTestsPattern} {
+		for _, loc := range pattern.FindAllStringSubmatchIndex(script, -1) {
+			matches = append(matches, match{index: loc[0], name: unquote(script[loc[2]:loc[3]])})
+		}
+	}
+
+	sort.Slice(matches, func(i, j int) bool { return matches[i].index < matches[j].index })
+
+	assertions := make([]string, 0, len(matches))
+	seen := map[string]bool{}
+	for _, m := range matches {
+		if !seen[m.name] {
+			seen[m.name] = true
+			assertions = append(assertions, m.name)
+		}
+	}
+	return assertions
+}
+
+// stripComments removes the Javascript comments of a script, leaving the string literals untouched.
+func stripComments(script string) string {
+	var b strings.Builder
+	for i := 0; i < len(script); i++ {
+		switch ch := script[i]; {
+		case ch == '"' || ch == '\'' || ch == '`':
+			end := i + 1
+			for end < len(script) && script[end] != ch {
+				if script[end] == '\\' {
+					end++
+				}
+				end++
+			}
+			if end >= len(script) {
+				end = len(script) - 1
+			}
+			b.WriteString(script[i : end+1])
+			i = end
+		case strings.HasPrefix(script[i:], "//"):
+			end := strings.IndexByte(script[i:], '\n')
+			if end < 0 {
+				return b.String()
+			}
+			i += end - 1
+		case strings.HasPrefix(script[i:], "/*"):
+			end := strings.Index(script[i+2:], "*/")
+			if end < 0 {
+				return b.String()
+			}
+			b.WriteByte(' ')
+			i += end + 3
+		default:
+			b.WriteByte(ch)
+		}
+	}
+	return b.String()
+}
+
+// unquote returns the value of a Javascript string literal.
+func unquote(literal string) string {
+	literal = literal[1 : len(literal)-1]
+	if !strings.Contains(literal, `\`) {
+		return literal
+	}
+
+	var b strings.Builder
+	for i := 0; i < len(literal); i++ {
+		if literal[i] != '\\' || i == len(literal)-1 {
+			b.WriteByte(literal[i])
+			continue
+		}
+		i++
+		switch literal[i] {
+		case 'n':
+			b.WriteByte('\n')
+		case 't':
+			b.WriteByte('\t')
+		default:
+			b.WriteByte(literal[i])
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,203 @@
+package postman
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"strings"
+	"unicode/utf8"
+)
+
+// TruncationMarker ends the bodies, and the JSON arrays, that are truncated.
+const TruncationMarker = "…"
+
+var binaryMediaTypes = map[string]bool{
+	"application/octet-stream": true,
+	"application/pdf":          true,
+	"application/zip":          true,
+	"application/gzip":         true,
+	"application/x-protobuf":   true,
+	"application/vnd.ms-excel": true,
+}
+
+// Truncated tells whether Body is truncated, the complete body then being FullBody.
+func (r Response) Truncated() bool {
+	return r.FullBody != ""
+}
+
+// CompleteBody returns the body of the response, before it is truncated.
+func (r Response) CompleteBody() string {
+	if r.Truncated() {
+		return r.FullBody
+	}
+	return r.Body
+}
+
+// IsImage tells whether the body of the response is an image, according to its Content-Type.
+func (r Response) IsImage() bool {
+	return strings.HasPrefix(r.ContentType, "image/")
+}
+
+// IsBinary tells whether the body of the response is not text, according to its Content-Type. Such bodies are better
+// rendered as previews or download links.
+func (r Response) IsBinary() bool {
+	for _, prefix := range []string{"image/", "audio/", "video/", "font/"} {
+		if strings.HasPrefix(r.ContentType, prefix) {
+			return r.ContentType != "image/svg+xml"
+		}
+	}
+	return binaryMediaTypes[r.ContentType]
+}
+
+// contentType returns the media type given by a Content-Type header, in lower case and without parameters.
+func contentType(headers []KeyValuePair) string {
+	for _, header := range headers {
+		if !strings.EqualFold(header.Key, "Content-Type") {
+			continue
+		}
+		value := strings.ToLower(fmt.Sprint(header.Value))
+		if mediaType, _, err := mime.ParseMediaType(value); err == nil {
+			return mediaType
+		}
+		return strings.TrimSpace(strings.Split(value, ";")[0])
+	}
+	return ""
+}
+
+// detectContentTypes sets the content type of the responses, before their headers are filtered.
+func (c *CollectionBuilder) detectContentTypes(col *Collection) {
+	walkRequests(col, func(_ []*Folder, request *Request) {
+		for i := range request.Responses {
+			request.Responses[i].ContentType = contentType(request.Responses[i].Headers)
+		}
+	})
+}
+
+// truncateBodies shortens the JSON arrays of the response bodies to maxArrayItems items, then the bodies to
+// maxBodySize bytes, keeping the complete bodies in FullBody. A zero limit disables the truncation.
+func (c *CollectionBuilder) truncateBodies(col *Collection, maxBodySize, maxArrayItems int) {
+	walkRequests(col, func(_ []*Folder, request *Request) {
+		for i := range request.Responses {
+			response := &request.Responses[i]
+			if body, truncated := truncateBody(response.Body, maxBodySize, maxArrayItems); truncated {
+				response.FullBody = response.Body
+				response.Body = body
+			}
+		}
+	})
+}
+
+func truncateBody(body string, maxBodySize, maxArrayItems int) (string, bool) {
+	truncated := false
+	if maxArrayItems > 0 {
+		if shortened, ok := truncateJSONArrays(body, maxArrayItems); ok {
+			body, truncated = shortened, true
+		}
+	}
+	if maxBodySize > 0 && len(body) > maxBodySize {
+		cut := maxBodySize
+		for cut > 0 && !utf8.RuneStart(body[cut]) {
+			cut--
+		}
+		body, truncated = body[:cut]+"\n"+TruncationMarker, true
+	}
+	return body, truncated
+}
+
+// truncateJSONArrays keeps the first items of the arrays of a JSON body, the other ones being replaced by a
+// "… N more item(s)" string so that the body remains valid JSON. It returns false when the body is not JSON, or
+// when no array is too long, the body then being left as is.
+func truncateJSONArrays(body string, maxItems int) (string, bool) {
+	decoder := json.NewDecoder(strings.NewReader(body))
+	decoder.UseNumber()
+	out := new(bytes.Buffer)
+	truncated, err := copyJSONValue(decoder, out, maxItems)
+	if err != nil || !truncated {
+		return body, false
+	}
+	if _, err := decoder.Token(); err != io.EOF {
+		return body, false
+	}
+	return out.String(), true
+}
+
+func copyJSONValue(decoder *json.Decoder, out *bytes.Buffer, maxItems int) (bool, error) {
+	token, err := decoder.Token()
+	if err != nil {
+		return false, err
+	}
+	switch t := token.(type) {
+	case json.Delim:
+		if t == '{' {
+			return copyJSONObject(decoder, out, maxItems)
+		}
+		return copyJSONArray(decoder, out, maxItems)
+	case nil:
+		out.WriteString("null")
+	case string:
+		writeJSONString(out, t)
+	default:
+		out.WriteString(fmt.Sprint(t))
+	}
+	return false, nil
+}
+
+func copyJSONObject(decoder *json.Decoder, out *bytes.Buffer, maxItems int) (bool, error) {
+	truncated := false
+	out.WriteByte('{')
+	for i := 0; decoder.More(); i++ {
+		key, err := decoder.Token()
+		if err != nil {
+			return false, err
+		}
+		if i > 0 {
+			out.WriteByte(',')
+		}
+		writeJSONString(out, key.(string))
+		out.WriteByte(':')
+		t, err := copyJSONValue(decoder, out, maxItems)
+		if err != nil {
+			return false, err
+		}
+		truncated = truncated || t
+	}
+	_, err := decoder.Token()
+	out.WriteByte('}')
+	return truncated, err
+}
+
+func copyJSONArray(decoder *json.Decoder, out *bytes.Buffer, maxItems int) (bool, error) {
+	truncated := false
+	out.WriteByte('[')
+	count := 0
+	for ; decoder.More(); count++ {
+		dest := out
+		if count >= maxItems {
+			dest = new(bytes.Buffer)
+		} else if count > 0 {
+			out.WriteByte(',')
+		}
+		t, err := copyJSONValue(decoder, dest, maxItems)
+		if err != nil {
+			return false, err
+		}
+		truncated = truncated || (t && count < maxItems)
+	}
+	if count > maxItems {
+		out.WriteByte(',')
+		writeJSONString(out, fmt.Sprintf("%v %d more item(s)", TruncationMarker, count-maxItems))
+		truncated = true
+	}
+	_, err := decoder.Token()
+	out.WriteByte(']')
+	return truncated, err
+}
+
+func writeJSONString(out *bytes.Buffer, value string) {
+	encoder := json.NewEncoder(out)
+	encoder.SetEscapeHTML(false)
+	encoder.Encode(value)
+	out.Truncate(out.Len() - 1)
+}
@@ -0,0 +1,90 @@
+package postman
+
+import (
+	"testing"
+)
+
+func TestTruncateBody(t *testing.T) {
+	testCases := []struct {
+		body              string
+		maxBodySize       int
+		maxArrayItems     int
+		expected          string
+		expectedTruncated bool
+	}{
+		{body: `[1, 2, 3]`, expected: `[1, 2, 3]`},
+		{body: `[1, 2, 3]`, maxArrayItems: 3, expected: `[1, 2, 3]`},
+		{body: `[1, 2, 3]`, maxArrayItems: 2, expected: `[1,2,"… 1 more item(s)"]`, expectedTruncated: true},
+		{body: `{"cats": [{"name": "<Felix>"}, {"name": "Tom"}], "total": 2}`, maxArrayItems: 1, expected: `{"cats":[{"name":"<Felix>"},"… 1 more item(s)"],"total":2}`, expectedTruncated: true},
+		{body: `{"ids": [[1, 2, 3], [4]], "id": 12345678901234567890}`, maxArrayItems: 2, expected: `{"ids":[[1,2,"… 1 more item(s)"],[4]],"id":12345678901234567890}`, expectedTruncated: true},
+		{body: `not json [1, 2, 3]`, maxArrayItems: 1, expected: `not json [1, 2, 3]`},
+		{body: `[1, 2] [3]`, maxArrayItems: 1, expected: `[1, 2] [3]`},
+		{body: `chat noir`, maxBodySize: 4, expected: "chat\n…", expectedTruncated: true},
+		{body: `chaînes`, maxBodySize: 4, expected: "cha\n…", expectedTruncated: true},
+		{body: `[1, 2, 3, 4, 5]`, maxBodySize: 8, maxArrayItems: 4, expected: "[1,2,3,4\n…", expectedTruncated: true},
+	}
+
+	for _, tc := range testCases {
+		// When
+		body, truncated := truncateBody(tc.body, tc.maxBodySize, tc.maxArrayItems)
+
+		// Then
+		if body != tc.expected || truncated != tc.expectedTruncated {
+			t.Errorf("Truncating %q to %d bytes and %d items, expected %q (%v), got %q (%v)",
+				tc.body, tc.maxBodySize, tc.maxArrayItems, tc.expected, tc.expectedTruncated, body, truncated)
+		}
+	}
+}
+
+func TestTruncateBodies(t *testing.T) {
+	// Given
+	builder := &CollectionBuilder{}
+	col := Collection{Folders: []Folder{{Requests: []Request{{Responses: []Response{
+		{Name: "Long", Body: `[1, 2, 3]`},
+		{Name: "Short", Body: `[1]`},
+	}}}}}}
+
+	// When
+	builder.truncateBodies(&col, 0, 1)
+
+	// Then
+	responses := col.Folders[0].Requests[0].Responses
+	if !responses[0].Truncated() || responses[0].FullBody != `[1, 2, 3]` || responses[0].CompleteBody() != `[1, 2, 3]` {
+		t.Errorf("Expected the long body to be truncated, got %+v", responses[0])
+	}
+	if responses[1].Truncated() || responses[1].CompleteBody() != `[1]` {
+		t.Errorf("Expected the short body to be kept, got %+v", responses[1])
+	}
+}
+
+func TestDetectContentTypes(t *testing.T) {
+	testCases := []struct {
+		headers        []KeyValuePair
+		expected       string
+		expectedImage  bool
+		expectedBinary bool
+	}{
+		{headers: []KeyValuePair{}, expected: ""},
+		{headers: []KeyValuePair{{Key: "content-type", Value: "application/json; charset=utf-8"}}, expected: "application/json"},
+		{headers: []KeyValuePair{{Key: "Content-Type", Value: "IMAGE/PNG"}}, expected: "image/png", expectedImage: true, expectedBinary: true},
+		{headers: []KeyValuePair{{Key: "Content-Type", Value: "image/svg+xml"}}, expected: "image/svg+xml", expectedImage: true},
+		{headers: []KeyValuePair{{Key: "Content-Type", Value: "application/pdf"}}, expected: "application/pdf", expectedBinary: true},
+		{headers: []KeyValuePair{{Key: "Content-Type", Value: "video/mp4;"}}, expected: "video/mp4", expectedBinary: true},
+	}
+
+	for _, tc := range testCases {
+		// Given
+		builder := &CollectionBuilder{}
+		col := Collection{Requests: []Request{{Responses: []Response{{Headers: tc.headers}}}}}
+
+		// When
+		builder.detectContentTypes(&col)
+
+		// Then
+		response := col.Requests[0].Responses[0]
+		if response.ContentType != tc.expected || response.IsImage() != tc.expectedImage || response.IsBinary() != tc.expectedBinary {
+			t.Errorf("With headers %v, expected %q (image: %v, binary: %v), got %q (image: %v, binary: %v)", tc.headers,
+				tc.expected, tc.expectedImage, tc.expectedBinary, response.ContentType, response.IsImage(), response.IsBinary())
+		}
+	}
+}
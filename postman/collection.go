@@ -1,26 +1,38 @@
 package postman
 
 type Collection struct {
-	Name        string
-	Description string
-	Requests    []Request
-	Folders     []Folder
-	Structures  []StructureDefinition
+	Name             string
+	Description      string
+	PreRequestScript string
+	Tests            string
+	Requests         []Request
+	Folders          []Folder
+	Structures       []StructureDefinition
+	Warnings         []string
+	// Redactions lists the secrets replaced by RedactedValue when BuilderOptions.Redact is set.
+	Redactions []Redaction
+	Meta       Meta
 }
 
 type Request struct {
-	ID            string
-	Name          string
-	Description   string
-	Method        string
-	URL           string
-	PayloadType   string
-	PayloadRaw    string
-	PayloadParams []KeyValuePair
-	PathVariables []KeyValuePair
-	Headers       []KeyValuePair
-	Responses     []Response
-	Tests         string
+	ID               string
+	Name             string
+	Description      string
+	Method           string
+	URL              string
+	PayloadType      string
+	PayloadRaw       string
+	PayloadParams    []KeyValuePair
+	PathVariables    []KeyValuePair
+	Headers          []KeyValuePair
+	Responses        []Response
+	PreRequestScript string
+	Tests            string
+	Assertions       []string
+	// BodyStructure is the structure of the request body, if known.
+	BodyStructure *StructureDefinition
+	Meta          Meta
+	Lifecycle     Lifecycle
 }
 
 type Response struct {
@@ -29,27 +41,79 @@ type Response struct {
 	Status     string
 	StatusCode int
 	Body       string
-	Headers    []KeyValuePair
+	// FullBody is the complete body when Body is truncated, see BuilderOptions.MaxBodySize.
+	FullBody string
+	// ContentType is the media type of the body, such as "image/png", read before the headers are filtered.
+	ContentType     string
+	Headers         []KeyValuePair
+	OriginalRequest *Request
+	// BodyStructure is the structure of the response body, if known.
+	BodyStructure *StructureDefinition
 }
 
 type Folder struct {
-	ID          string
-	Name        string
-	Description string
-	Folders     []Folder
-	Requests    []Request
+	ID               string
+	Name             string
+	Description      string
+	PreRequestScript string
+	Tests            string
+	Folders          []Folder
+	Requests         []Request
+	Meta             Meta
 }
 
 type StructureDefinition struct {
 	Name        string
 	Description string
 	Fields      []StructureFieldDefinition
+	// Inferred is true for the structures guessed from the examples of a request, see BuilderOptions.InferStructures.
+	Inferred bool
 }
 
+// StructureFieldDefinition describes a field of a structure. Its type is either a primitive type
+// or the name of another structure, see Collection.FindStructure.
 type StructureFieldDefinition struct {
 	Name        string
 	Description string
 	Type        string
+	Format      string
+	Required    bool
+	Nullable    bool
+	Enum        []interface{}
+	Example     interface{}
+	Default     interface{}
+	// Fields holds the fields of an object, and Items describes the elements of an array.
+	Fields []StructureFieldDefinition
+	Items  *StructureFieldDefinition
+}
+
+// FindStructure returns the structure with the given name, or nil if there is none.
+func (c Collection) FindStructure(name string) *StructureDefinition {
+	for _, structure := range c.Structures {
+		if structure.Name == name {
+			return &structure
+		}
+	}
+	return nil
+}
+
+// walkRequests calls fn with each request of the collection, which can be modified in place, and with the folders
+// leading to it, from the outermost one. The requests of a folder come before the ones of its sub folders.
+func walkRequests(col *Collection, fn func(path []*Folder, r *Request)) {
+	for i := range col.Requests {
+		fn(nil, &col.Requests[i])
+	}
+	walkFolderRequests(col.Folders, nil, fn)
+}
+
+func walkFolderRequests(folders []Folder, path []*Folder, fn func(path []*Folder, r *Request)) {
+	for i := range folders {
+		folderPath := append(path[:len(path):len(path)], &folders[i])
+		for j := range folders[i].Requests {
+			fn(folderPath, &folders[i].Requests[j])
+		}
+		walkFolderRequests(folders[i].Folders, folderPath, fn)
+	}
 }
 
 type KeyValuePair struct {
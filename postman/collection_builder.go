@@ -5,10 +5,10 @@ import (
 	"errors"
 	"fmt"
 	"io/ioutil"
-	"regexp"
 	"strings"
+	"time"
 
-	"github.com/fatih/color"
+	"github.com/aubm/postmanerator/utils"
 	"github.com/robertkrimen/otto"
 )
 
@@ -35,7 +35,45 @@ func (c *CollectionBuilder) FromFile(file string, options BuilderOptions) (col C
 		return col, err
 	}
 
-	c.extractStructuresDefinition(&col)
+	c.extractMeta(&col)
+	c.extractLifecycles(&col)
+	c.detectContentTypes(&col)
+	if options.Documentation {
+		if err := c.filterHeaders(&col, options); err != nil {
+			return col, err
+		}
+	}
+	if err := c.extractStructuresDefinition(&col, options); err != nil {
+		return col, err
+	}
+	if err := c.sortCollection(&col, options.Sort); err != nil {
+		return col, err
+	}
+	if filter := newPathFilter(options.Include, options.Exclude); filter.active() {
+		filter.apply(&col)
+	}
+	if filter := newTagFilter(options.Tags); filter.active() {
+		filter.apply(&col)
+	}
+	if options.Documentation && (options.Redact || len(options.RedactPatterns) > 0) {
+		redactor, err := newRedactor(options.RedactPatterns)
+		if err != nil {
+			return col, err
+		}
+		redactor.redactCollection(&col)
+	}
+	if options.InferStructures {
+		c.inferStructures(&col)
+	}
+	c.linkBodyStructures(&col, options)
+	c.extractAssertions(&col)
+	if options.Documentation && (options.MaxBodySize > 0 || options.MaxArrayItems > 0) {
+		c.truncateBodies(&col, options.MaxBodySize, options.MaxArrayItems)
+	}
+
+	if options.Strict && len(col.Warnings) > 0 {
+		return col, fmt.Errorf("the collection has %d warning(s):\n%v", len(col.Warnings), strings.Join(col.Warnings, "\n"))
+	}
 	return col, nil
 }
 
@@ -63,144 +101,248 @@ func (c *CollectionBuilder) parseCollection(contents []byte, options BuilderOpti
 	return Collection{}, ErrAllParsersFailed
 }
 
-func (c *CollectionBuilder) extractStructuresDefinition(col *Collection) {
-	structureDefinitions := make([]StructureDefinition, 0)
-
-	tests := c.extractCollectionTests(col)
+// extractStructuresDefinition gathers the structures of the structures file, of the collection and folder descriptions
+// and of the test scripts, in this order. When several structures have the same name, the first one is kept.
+func (c *CollectionBuilder) extractStructuresDefinition(col *Collection, options BuilderOptions) error {
+	structures := make([]sourcedStructure, 0)
 
-	codeFragments := make([]string, 0)
-	for _, t := range tests {
-		codeFragments = append(codeFragments, c.extractCodeFragments(t)...)
+	if options.StructuresFile != "" {
+		fileStructures, err := c.readStructuresFile(col, options.StructuresFile)
+		if err != nil {
+			return err
+		}
+		structures = append(structures, fileStructures...)
 	}
+	structures = append(structures, c.extractDescriptionStructures(col)...)
+	structures = append(structures, c.extractScriptStructures(col, options)...)
 
-	vm := otto.New()
-	vm.Set("APIStructures", struct{}{})
-	color.Set(color.FgCyan)
-	for _, frag := range codeFragments {
-		frag = frag + `
-if (!!populateNewAPIStructures && typeof(populateNewAPIStructures) === 'function') {
-    populateNewAPIStructures();
-}`
-		vm.Run(frag)
-	}
-	color.Unset()
-	if value, err := vm.Get("APIStructures"); err == nil {
-		if apiStructures := value.Object(); apiStructures != nil {
-			for _, key := range apiStructures.Keys() {
-				if structureDef, err := apiStructures.Get(key); err == nil {
-					if structure, err := c.getStructureDefinition(structureDef); err == nil {
-						structureDefinitions = append(structureDefinitions, structure)
-					} else {
-						fmt.Println(err)
-					}
-				}
-			}
+	sources := map[string]string{}
+	structureDefinitions := make([]StructureDefinition, 0, len(structures))
+	for _, structure := range structures {
+		if source, ok := sources[structure.Name]; ok {
+			col.Warnings = append(col.Warnings, fmt.Sprintf("%v: structure %v is already defined in %v", structure.source, structure.Name, source))
+			continue
 		}
+		sources[structure.Name] = structure.source
+		structureDefinitions = append(structureDefinitions, structure.StructureDefinition)
 	}
-
 	col.Structures = structureDefinitions
+	return nil
 }
 
-func (c *CollectionBuilder) extractCollectionTests(col *Collection) []string {
-	f := Folder{Folders: col.Folders, Requests: col.Requests}
-	return c.extractFolderTests(f)
-}
-
-func (c *CollectionBuilder) extractFolderTests(folder Folder) []string {
-	tests := make([]string, 0)
-	for _, req := range folder.Requests {
-		tests = append(tests, req.Tests)
+func (c *CollectionBuilder) readStructuresFile(col *Collection, file string) ([]sourcedStructure, error) {
+	contents, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read structures file: %v", err)
 	}
-	for _, f := range folder.Folders {
-		tests = append(tests, c.extractFolderTests(f)...)
+	reader := &structureReader{}
+	structures, err := reader.parseDocument(contents)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse structures file %v: %v", file, err)
 	}
-	return tests
+	for _, warning := range reader.warnings {
+		col.Warnings = append(col.Warnings, fmt.Sprintf("%v: %v", file, warning))
+	}
+	return withSource(file, structures), nil
 }
 
-func (c *CollectionBuilder) extractCodeFragments(input string) []string {
-	var codeFragments []string
-	var fragment string
-	validID := regexp.MustCompile(`/\*\[\[(start|end) postmanerator]]\*/`)
-	for validID.MatchString(input) {
-		fragment, input = c.nextCodeFragment(input)
-		codeFragments = append(codeFragments, fragment)
+// extractDescriptionStructures reads the structure blocks of the collection and folder descriptions,
+// and removes them from the descriptions.
+func (c *CollectionBuilder) extractDescriptionStructures(col *Collection) []sourcedStructure {
+	var blocks []string
+	col.Description, blocks = extractDescriptionStructures(col.Description)
+	structures := c.parseStructureBlocks(col, col.Name, blocks)
+	for i := range col.Folders {
+		structures = append(structures, c.extractFolderDescriptionStructures(col, &col.Folders[i])...)
 	}
-
-	return codeFragments
+	return structures
 }
 
-func (c *CollectionBuilder) nextCodeFragment(input string) (string, string) {
-	startTag := "/*[[start postmanerator]]*/"
-	endTag := "/*[[end postmanerator]]*/"
-	parts := strings.Split(input, startTag)
-	parts = parts[1:]
-	input = strings.Join(parts, startTag)
-	parts = strings.Split(input, endTag)
-	return strings.Trim(parts[0], "\n"), strings.Join(parts[1:], endTag)
+func (c *CollectionBuilder) extractFolderDescriptionStructures(col *Collection, folder *Folder) []sourcedStructure {
+	var blocks []string
+	folder.Description, blocks = extractDescriptionStructures(folder.Description)
+	structures := c.parseStructureBlocks(col, folder.Name, blocks)
+	for i := range folder.Folders {
+		structures = append(structures, c.extractFolderDescriptionStructures(col, &folder.Folders[i])...)
+	}
+	return structures
 }
 
-func (c *CollectionBuilder) getStructureDefinition(srcVal otto.Value) (StructureDefinition, error) {
-	var structDef StructureDefinition
-	if !srcVal.IsObject() {
-		return structDef, errors.New("value is not an object")
+func (c *CollectionBuilder) parseStructureBlocks(col *Collection, owner string, blocks []string) []sourcedStructure {
+	structures := make([]sourcedStructure, 0)
+	source := fmt.Sprintf("%v description", owner)
+	for _, block := range blocks {
+		reader := &structureReader{}
+		blockStructures, err := reader.parseDocument([]byte(block))
+		for _, warning := range reader.warnings {
+			col.Warnings = append(col.Warnings, fmt.Sprintf("%v: %v", source, warning))
+		}
+		if err != nil {
+			col.Warnings = append(col.Warnings, fmt.Sprintf("%v: invalid structure block: %v", source, err))
+		}
+		structures = append(structures, withSource(source, blockStructures)...)
 	}
+	return structures
+}
 
-	srcObj := srcVal.Object()
+func (c *CollectionBuilder) extractScriptStructures(col *Collection, options BuilderOptions) []sourcedStructure {
+	timeout := options.ScriptTimeout
+	if timeout == 0 {
+		timeout = defaultScriptTimeout
+	}
 
-	// Get structure name
-	nameVal, err := srcObj.Get("name")
-	if err != nil {
-		return structDef, errors.New("structures must have a name")
+	names := make([]string, 0)
+	structures := map[string]sourcedStructure{}
+	for _, script := range c.extractCollectionTests(col) {
+		for _, frag := range c.extractCodeFragments(script) {
+			reader := &structureReader{}
+			keys, values, err := c.runCodeFragment(reader, frag, timeout)
+			for _, warning := range reader.warnings {
+				col.Warnings = append(col.Warnings, frag.errorf(0, "%v", warning).Error())
+			}
+			if err != nil {
+				col.Warnings = append(col.Warnings, err.Error())
+			}
+			for i, key := range keys {
+				if _, ok := structures[key]; !ok {
+					names = append(names, key)
+				}
+				structures[key] = sourcedStructure{source: fmt.Sprintf("%v tests", frag.owner), StructureDefinition: values[i]}
+			}
+		}
 	}
-	structDef.Name = nameVal.String()
 
-	// Get structure description
-	descVal, err := srcObj.Get("description")
-	if err != nil {
-		return structDef, errors.New("structures must have a description")
+	scriptStructures := make([]sourcedStructure, 0, len(names))
+	for _, name := range names {
+		scriptStructures = append(scriptStructures, structures[name])
 	}
-	structDef.Description = descVal.String()
+	return scriptStructures
+}
 
-	// Get structure fields
-	fieldsVal, err := srcObj.Get("fields")
-	if err != nil {
-		return structDef, errors.New("structures must define fields")
+// runCodeFragment runs a code fragment in its own VM, and returns the structures it defines by key.
+func (c *CollectionBuilder) runCodeFragment(reader *structureReader, frag codeFragment, timeout time.Duration) ([]string, []StructureDefinition, error) {
+	vm := otto.New()
+	if _, err := vm.Run(structuresPrelude); err != nil {
+		return nil, nil, frag.errorf(0, "%v", err)
 	}
-	fields, err := fieldsVal.Export()
-	if err != nil {
-		return structDef, errors.New("failed to convert javascript fields attribute into a valid go type")
+	if _, err := utils.RunScript(vm, frag.source+structuresEpilogue, timeout); err != nil {
+		line, message := describeScriptError(err)
+		return nil, nil, frag.errorf(line, "%v", message)
 	}
-	fieldsSlice, ok := fields.([]map[string]interface{})
-	if !ok {
-		return structDef, errors.New("fields attribute must be an array of objects")
-	}
-	for _, fieldDefMap := range fieldsSlice {
-		// Get field name
-		var fieldDef StructureFieldDefinition
-		if fieldName, ok := fieldDefMap["name"].(string); ok == true {
-			fieldDef.Name = fieldName
-		} else {
-			return structDef, errors.New("structure fields must have a name")
-		}
 
-		// Get field description
-		if fieldDesc, ok := fieldDefMap["description"].(string); ok == true {
-			fieldDef.Description = fieldDesc
+	keys := make([]string, 0)
+	values := make([]StructureDefinition, 0)
+	value, err := vm.Get("APIStructures")
+	if err != nil || !value.IsObject() {
+		return keys, values, frag.errorf(0, "APIStructures must be an object")
+	}
+	apiStructures := value.Object()
+	for _, key := range apiStructures.Keys() {
+		structureDef, err := apiStructures.Get(key)
+		if err != nil {
+			return keys, values, frag.errorf(0, "%v", err)
 		}
-
-		// Get field type
-		if fieldType, ok := fieldDefMap["type"].(string); ok == true {
-			fieldDef.Type = fieldType
+		structure, err := c.getStructureDefinition(reader, structureDef)
+		if err != nil {
+			return keys, values, frag.errorf(0, "invalid structure %v: %v", key, err)
 		}
+		keys = append(keys, key)
+		values = append(values, structure)
+	}
+	return keys, values, nil
+}
+
+func (c *CollectionBuilder) extractCollectionTests(col *Collection) []Script {
+	f := Folder{Name: col.Name, Tests: col.Tests, Folders: col.Folders, Requests: col.Requests}
+	return c.extractFolderTests(f)
+}
 
-		structDef.Fields = append(structDef.Fields, fieldDef)
+func (c *CollectionBuilder) extractFolderTests(folder Folder) []Script {
+	tests := appendScript(make([]Script, 0), folder.Name, folder.Tests)
+	for _, req := range folder.Requests {
+		tests = appendScript(tests, req.Name, req.Tests)
+	}
+	for _, f := range folder.Folders {
+		tests = append(tests, c.extractFolderTests(f)...)
 	}
+	return tests
+}
 
-	return structDef, nil
+// extractCodeFragments returns the code found between the postmanerator delimiters of a script,
+// along with the line where each fragment starts.
+func (c *CollectionBuilder) extractCodeFragments(script Script) []codeFragment {
+	codeFragments := make([]codeFragment, 0)
+	for offset := 0; ; {
+		start := strings.Index(script.Source[offset:], fragmentStartTag)
+		if start < 0 {
+			break
+		}
+		start += offset + len(fragmentStartTag)
+		end := strings.Index(script.Source[start:], fragmentEndTag)
+		if end < 0 {
+			break
+		}
+		end += start
+		codeFragments = append(codeFragments, codeFragment{
+			owner:  script.Owner,
+			line:   strings.Count(script.Source[:start], "\n") + 1,
+			source: script.Source[start:end],
+		})
+		offset = end + len(fragmentEndTag)
+	}
+	return codeFragments
+}
+
+func (c *CollectionBuilder) getStructureDefinition(reader *structureReader, srcVal otto.Value) (StructureDefinition, error) {
+	if !srcVal.IsObject() {
+		return StructureDefinition{}, errors.New("value is not an object")
+	}
+	exported, err := srcVal.Export()
+	if err != nil {
+		return StructureDefinition{}, errors.New("failed to convert javascript structure into a valid go type")
+	}
+	src, ok := normalize(exported).(object)
+	if !ok {
+		return StructureDefinition{}, errors.New("value is not an object")
+	}
+	return reader.fromObject(src)
 }
 
 type BuilderOptions struct {
+	// Documentation applies the transformations meant for a generated documentation: the header filters, Redact and
+	// the body limits. They are left out otherwise, so that the verify, mock and test commands see the original values.
+	Documentation bool
+	// IgnoredRequestHeaders and IgnoredResponseHeaders are case insensitive header names, globs such as "X-Amz-*",
+	// or regular expressions between slashes, matching the headers that are not documented.
 	IgnoredRequestHeaders  []string
 	IgnoredResponseHeaders []string
+	// AllowedRequestHeaders and AllowedResponseHeaders are header patterns too, only the matching headers being
+	// documented when they are set.
+	AllowedRequestHeaders  []string
+	AllowedResponseHeaders []string
 	EnvironmentVariables   Environment
+	// StructuresFile is a YAML or JSON file defining structures, in addition to the ones found in the collection.
+	StructuresFile string
+	// Include and Exclude are glob patterns selecting folders and requests by path, such as "Admin/**".
+	Include []string
+	Exclude []string
+	// Tags selects the requests by the tags of their metadata, a tag starting with "!" excluding the requests.
+	Tags []string
+	// Sort is the order of the requests: SortAlpha, SortMethod, SortPath or SortNone, the default.
+	Sort string
+	// Redact replaces the secrets of the headers, URLs and bodies, such as authorization headers, tokens and JWTs.
+	Redact bool
+	// RedactPatterns are regular expressions whose matches are redacted too, only the first group when there is one.
+	// They imply Redact.
+	RedactPatterns []string
+	// MaxBodySize and MaxArrayItems truncate the response bodies longer than a number of bytes, and the JSON arrays
+	// of the response bodies longer than a number of items. Zero means no limit.
+	MaxBodySize   int
+	MaxArrayItems int
+	// InferStructures adds structures inferred from the JSON bodies of the examples of each request.
+	InferStructures bool
+	// Strict makes FromFile fail when the collection has warnings.
+	Strict bool
+	// ScriptTimeout limits the execution time of each structure definition fragment, default is 5 seconds.
+	ScriptTimeout time.Duration
 }
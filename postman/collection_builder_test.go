@@ -1,8 +1,11 @@
 package postman
 
 import (
+	"io/ioutil"
+	"os"
 	"reflect"
 	"testing"
+	"time"
 )
 
 func TestExtractStructuresDefinition(t *testing.T) {
@@ -34,3 +37,190 @@ func TestExtractStructuresDefinition(t *testing.T) {
 			expectedStructures, col.Structures)
 	}
 }
+
+func TestExtractAssertions(t *testing.T) {
+	// Given
+	builder := &CollectionBuilder{}
+	builder.Parsers = append(builder.Parsers, &CollectionV210Parser{})
+
+	// When
+	col, err := builder.FromFile("tests_data/collection-01.json", BuilderOptions{})
+
+	// Then
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	expectedAssertions := []string{"has 1 entry", "expect name", "expect color"}
+	if assertions := col.Folders[0].Requests[1].Assertions; !reflect.DeepEqual(assertions, expectedAssertions) {
+		t.Errorf("Assertions were not properly extracted, expected %v, got %v", expectedAssertions, assertions)
+	}
+	if assertions := col.Folders[1].Requests[0].Assertions; len(assertions) != 0 {
+		t.Errorf("Requests without tests should not have assertions, got %v", assertions)
+	}
+}
+
+func TestExtractAssertionsFromScript(t *testing.T) {
+	// Given
+	script := `pm.test("returns 201", function () {
+    pm.response.to.have.status(201);
+});
+pm.test('body contains "id"', () => pm.expect(pm.response.json()).to.have.property("id"));
+// pm.test("commented out", function () {});
+/* tests["commented out too"] = true; */
+tests["legacy \"quoted\" test"] = responseCode.code === 201;
+if (tests["not an assignment"] === true) {}
+pm.test.skip("skipped", function () {});
+pm.test("returns 201", function () {});
+pm.test(` + "`template // literal`" + `, function () {});`
+
+	// When
+	assertions := extractAssertions(script)
+
+	// Then
+	expectedAssertions := []string{"returns 201", `body contains "id"`, `legacy "quoted" test`, "template // literal"}
+	if !reflect.DeepEqual(assertions, expectedAssertions) {
+		t.Errorf("Assertions were not properly extracted, expected %q, got %q", expectedAssertions, assertions)
+	}
+}
+
+func TestExtractStructuresDefinitionWarnings(t *testing.T) {
+	// Given
+	builder := &CollectionBuilder{}
+	col := Collection{
+		Name:  "Cats API",
+		Tests: "/*[[start postmanerator]]*/\nvar leaked = true;\n/*[[end postmanerator]]*/",
+		Requests: []Request{
+			{Name: "Syntax error", Tests: "// a comment\n/*[[start postmanerator]]*/\nvar x = ;\n/*[[end postmanerator]]*/"},
+			{Name: "Runtime error", Tests: `tests["ok"] = true;
+/*[[start postmanerator]]*/
+function populateNewAPIStructures() {
+    if (leaked) {}
+}
+/*[[end postmanerator]]*/`},
+			{Name: "Infinite loop", Tests: "/*[[start postmanerator]]*/\nwhile (true) {}\n/*[[end postmanerator]]*/"},
+			{Name: "Invalid structure", Tests: `/*[[start postmanerator]]*/
+function populateNewAPIStructures() {
+    APIStructures['cat'] = {name: 'Cat', description: 'A cat', fields: 'none'};
+}
+/*[[end postmanerator]]*/`},
+			{Name: "Valid structure", Tests: `/*[[start postmanerator]]*/
+function populateNewAPIStructures() {
+    console.log('not printed');
+    APIStructures['dog'] = {name: 'Dog', description: 'A dog', fields: [{name: 'id', required: true, colour: 'brown'}]};
+}
+/*[[end postmanerator]]*/`},
+		},
+	}
+
+	// When
+	builder.extractStructuresDefinition(&col, BuilderOptions{ScriptTimeout: 50 * time.Millisecond})
+
+	// Then
+	expectedWarnings := []string{
+		"Syntax error, line 3: Unexpected token ;",
+		"Runtime error, line 4: ReferenceError: 'leaked' is not defined",
+		"Infinite loop, line 1: script timed out",
+		"Invalid structure, line 1: invalid structure cat: fields attribute must be an array of objects",
+		`Valid structure, line 1: structure Dog, field id: unknown attribute "colour"`,
+	}
+	if !reflect.DeepEqual(col.Warnings, expectedWarnings) {
+		t.Errorf("Warnings were not properly collected, expected %q, got %q", expectedWarnings, col.Warnings)
+	}
+	if len(col.Structures) != 1 || col.Structures[0].Name != "Dog" || !col.Structures[0].Fields[0].Required {
+		t.Errorf("Valid structures should still be extracted, got %v", col.Structures)
+	}
+}
+
+func TestFromFileStrict(t *testing.T) {
+	// Given
+	builder := &CollectionBuilder{}
+	builder.Parsers = append(builder.Parsers, &CollectionV210Parser{})
+	file, err := ioutil.TempFile("", "postmanerator-collection")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(file.Name())
+	file.WriteString(`{
+	"info": {"name": "Cats API"},
+	"item": [{
+		"name": "Get all cats",
+		"event": [{"listen": "test", "script": {"exec": ["/*[[start postmanerator]]*/", "undefinedFunction();", "/*[[end postmanerator]]*/"]}}],
+		"request": {"method": "GET", "url": "http://{{domain}}/api/cats"}
+	}]
+}`)
+	file.Close()
+
+	// When
+	col, err := builder.FromFile(file.Name(), BuilderOptions{})
+
+	// Then
+	if err != nil {
+		t.Errorf("Warnings should not fail the build by default, got %v", err)
+	}
+	if len(col.Warnings) != 1 {
+		t.Errorf("Expected one warning, got %q", col.Warnings)
+	}
+
+	// When
+	_, err = builder.FromFile(file.Name(), BuilderOptions{Strict: true})
+
+	// Then
+	expectedError := "the collection has 1 warning(s):\nGet all cats, line 2: ReferenceError: 'undefinedFunction' is not defined"
+	if err == nil || err.Error() != expectedError {
+		t.Errorf("Expected error %q, got %v", expectedError, err)
+	}
+}
+
+func TestFromFileDocumentationTransformations(t *testing.T) {
+	// Given
+	builder := &CollectionBuilder{}
+	builder.Parsers = append(builder.Parsers, &CollectionV210Parser{})
+	file, err := ioutil.TempFile("", "postmanerator-collection")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(file.Name())
+	file.WriteString(`{
+	"info": {"name": "Cats API"},
+	"item": [{
+		"name": "Get all cats",
+		"request": {"method": "GET", "url": "http://my-api/cats", "header": [{"key": "Authorization", "value": "Bearer s3cr3t"}, {"key": "X-Request-Id", "value": "42"}]},
+		"response": [{"name": "OK", "code": 200, "body": "[{\"name\": \"felix\"}, {\"name\": \"tom\"}]"}]
+	}]
+}`)
+	file.Close()
+	options := BuilderOptions{IgnoredRequestHeaders: []string{"X-Request-Id"}, Redact: true, MaxArrayItems: 1}
+
+	// When
+	col, err := builder.FromFile(file.Name(), options)
+
+	// Then
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	request := col.Requests[0]
+	expectedHeaders := []KeyValuePair{{Name: "Authorization", Key: "Authorization", Value: "Bearer s3cr3t"}, {Name: "X-Request-Id", Key: "X-Request-Id", Value: "42"}}
+	if !reflect.DeepEqual(request.Headers, expectedHeaders) {
+		t.Errorf("Expected the original headers %v, got %v", expectedHeaders, request.Headers)
+	}
+	if body := request.Responses[0].Body; body != `[{"name": "felix"}, {"name": "tom"}]` {
+		t.Errorf("Expected the original body, got %v", body)
+	}
+
+	// When
+	options.Documentation = true
+	col, err = builder.FromFile(file.Name(), options)
+
+	// Then
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	request = col.Requests[0]
+	expectedHeaders = []KeyValuePair{{Name: "Authorization", Key: "Authorization", Value: "Bearer [REDACTED]"}}
+	if !reflect.DeepEqual(request.Headers, expectedHeaders) {
+		t.Errorf("Expected the documented headers %v, got %v", expectedHeaders, request.Headers)
+	}
+	if response := request.Responses[0]; response.FullBody == "" || response.Body == response.FullBody {
+		t.Errorf("Expected a truncated body, got %v", response.Body)
+	}
+}
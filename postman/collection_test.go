@@ -0,0 +1,39 @@
+package postman
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestWalkRequests(t *testing.T) {
+	// Given
+	col := Collection{
+		Requests: []Request{{Name: "Get all cats"}},
+		Folders: []Folder{
+			{Name: "Dogs", Requests: []Request{{Name: "Get all dogs"}}, Folders: []Folder{
+				{Name: "Toys", Requests: []Request{{Name: "Get all toys"}}},
+			}},
+			{Name: "Birds", Requests: []Request{{Name: "Get all birds"}}},
+		},
+	}
+
+	// When
+	visited := make([]string, 0)
+	walkRequests(&col, func(path []*Folder, r *Request) {
+		location := ""
+		for _, folder := range path {
+			location = joinPath(location, folder.Name)
+		}
+		visited = append(visited, joinPath(location, r.Name))
+		r.Description = "visited"
+	})
+
+	// Then
+	expected := []string{"Get all cats", "Dogs/Get all dogs", "Dogs/Toys/Get all toys", "Birds/Get all birds"}
+	if !reflect.DeepEqual(visited, expected) {
+		t.Errorf("Expected the requests %v, got %v", expected, visited)
+	}
+	if col.Folders[0].Folders[0].Requests[0].Description != "visited" {
+		t.Errorf("Expected the requests to be modified in place")
+	}
+}
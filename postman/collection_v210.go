@@ -1,41 +1,62 @@
 package postman
 
+import "encoding/json"
+
 type collectionV210 struct {
 	Info struct {
 		Name        string `json:"name"`
 		Description string `json:"description"`
 		Schema      string `json:"schema"`
 	} `json:"info"`
-	Item []collectionV210Item `json:"item"`
+	Event []collectionV210Event `json:"event,omitempty"`
+	Item  []collectionV210Item  `json:"item"`
 }
 
 type collectionV210Item struct {
-	Name        string                `json:"name"`
-	Description string                `json:"description"`
-	Event       []collectionV210Event `json:"event"`
-	Item        []collectionV210Item  `json:"item"`
-	Request     *struct {
-		Method string                       `json:"method"`
-		Header []collectionV210KeyValuePair `json:"header"`
-		Body   struct {
-			Mode       string                       `json:"mode"`
-			Raw        string                       `json:"raw"`
-			FormData   []collectionV210KeyValuePair `json:"formdata,omitempty"`
-			UrlEncoded []collectionV210KeyValuePair `json:"urlencoded,omitempty"`
-		} `json:"body"`
-		Url struct {
-			Raw      string                       `json:"raw"`
-			Variable []collectionV210KeyValuePair `json:"variable"`
-		} `json:"url"`
-		Description string `json:"description"`
-	} `json:"request,omitempty"`
-	Response []struct {
-		Name   string                       `json:"name"`
-		Status string                       `json:"status"`
-		Code   int                          `json:"code"`
-		Header []collectionV210KeyValuePair `json:"header"`
-		Body   string                       `json:"body"`
-	} `json:"response"`
+	Name        string                   `json:"name"`
+	Description string                   `json:"description"`
+	Event       []collectionV210Event    `json:"event,omitempty"`
+	Item        []collectionV210Item     `json:"item,omitempty"`
+	Request     *collectionV210Request   `json:"request,omitempty"`
+	Response    []collectionV210Response `json:"response,omitempty"`
+}
+
+type collectionV210Request struct {
+	Method string                       `json:"method"`
+	Header []collectionV210KeyValuePair `json:"header"`
+	Body   struct {
+		Mode       string                       `json:"mode"`
+		Raw        string                       `json:"raw"`
+		FormData   []collectionV210KeyValuePair `json:"formdata,omitempty"`
+		UrlEncoded []collectionV210KeyValuePair `json:"urlencoded,omitempty"`
+	} `json:"body"`
+	Url         collectionV210Url `json:"url"`
+	Description string            `json:"description"`
+}
+
+type collectionV210Url struct {
+	Raw      string                       `json:"raw"`
+	Variable []collectionV210KeyValuePair `json:"variable,omitempty"`
+}
+
+// UnmarshalJSON accepts both the object form and the plain string form Postman uses for URLs.
+func (u *collectionV210Url) UnmarshalJSON(data []byte) error {
+	var raw string
+	if err := json.Unmarshal(data, &raw); err == nil {
+		u.Raw = raw
+		return nil
+	}
+	type plainUrl collectionV210Url
+	return json.Unmarshal(data, (*plainUrl)(u))
+}
+
+type collectionV210Response struct {
+	Name            string                       `json:"name"`
+	OriginalRequest *collectionV210Request       `json:"originalRequest,omitempty"`
+	Status          string                       `json:"status"`
+	Code            int                          `json:"code"`
+	Header          []collectionV210KeyValuePair `json:"header"`
+	Body            string                       `json:"body"`
 }
 
 type collectionV210Event struct {
@@ -19,20 +19,22 @@ func (p *CollectionV210Parser) Parse(contents []byte, options BuilderOptions) (C
 	if err := json.Unmarshal(contents, &src); err != nil {
 		return Collection{}, err
 	}
-	return p.buildCollection(src, options)
+	return p.buildCollection(src)
 }
 
-func (p *CollectionV210Parser) buildCollection(src collectionV210, options BuilderOptions) (Collection, error) {
+func (p *CollectionV210Parser) buildCollection(src collectionV210) (Collection, error) {
 	collection := Collection{
-		Name:        src.Info.Name,
-		Description: src.Info.Description,
-		Requests:    make([]Request, 0),
-		Folders:     make([]Folder, 0),
-		Structures:  make([]StructureDefinition, 0),
+		Name:             src.Info.Name,
+		Description:      src.Info.Description,
+		PreRequestScript: p.parseEventScript(src.Event, "prerequest"),
+		Tests:            p.parseEventScript(src.Event, "test"),
+		Requests:         make([]Request, 0),
+		Folders:          make([]Folder, 0),
+		Structures:       make([]StructureDefinition, 0),
 	}
 
 	rootItem := Folder{}
-	if err := p.computeItem(&rootItem, src.Item, options); err != nil {
+	if err := p.computeItem(&rootItem, src.Item); err != nil {
 		return collection, fmt.Errorf("failed to build request: %v", err)
 	}
 
@@ -42,33 +44,26 @@ func (p *CollectionV210Parser) buildCollection(src collectionV210, options Build
 	return collection, nil
 }
 
-func (p *CollectionV210Parser) computeItem(parentFolder *Folder, items []collectionV210Item, options BuilderOptions) error {
+func (p *CollectionV210Parser) computeItem(parentFolder *Folder, items []collectionV210Item) error {
 	for _, item := range items {
 		if item.Request == nil { // item is a folder
 			folder := Folder{
-				ID:          uuid.NewV4().String(),
-				Description: item.Description,
-				Name:        item.Name,
+				ID:               uuid.NewV4().String(),
+				Description:      item.Description,
+				Name:             item.Name,
+				PreRequestScript: p.parseEventScript(item.Event, "prerequest"),
+				Tests:            p.parseEventScript(item.Event, "test"),
 			}
-			if err := p.computeItem(&folder, item.Item, options); err != nil {
+			if err := p.computeItem(&folder, item.Item); err != nil {
 				return err
 			}
 			parentFolder.Folders = append(parentFolder.Folders, folder)
 		} else { // item is a request
-			request := Request{
-				ID:            uuid.NewV4().String(),
-				Name:          item.Name,
-				Description:   item.Request.Description,
-				Method:        item.Request.Method,
-				URL:           item.Request.Url.Raw,
-				PayloadType:   item.Request.Body.Mode,
-				PayloadRaw:    item.Request.Body.Raw,
-				Tests:         p.parseRequestTests(item),
-				PathVariables: p.parseRequestPathVariables(item),
-				PayloadParams: p.parseRequestPayloadParams(item),
-				Headers:       p.parseRequestHeaders(item, options),
-				Responses:     p.parseRequestResponses(item, options),
-			}
+			request := p.buildRequest(*item.Request)
+			request.Name = item.Name
+			request.PreRequestScript = p.parseEventScript(item.Event, "prerequest")
+			request.Tests = p.parseEventScript(item.Event, "test")
+			request.Responses = p.parseRequestResponses(item)
 			parentFolder.Requests = append(parentFolder.Requests, request)
 		}
 	}
@@ -76,19 +71,33 @@ func (p *CollectionV210Parser) computeItem(parentFolder *Folder, items []collect
 	return nil
 }
 
-func (p *CollectionV210Parser) parseRequestTests(item collectionV210Item) string {
-	for _, event := range item.Event {
-		if event.Listen == "test" {
+func (p *CollectionV210Parser) buildRequest(src collectionV210Request) Request {
+	return Request{
+		ID:            uuid.NewV4().String(),
+		Description:   src.Description,
+		Method:        src.Method,
+		URL:           src.Url.Raw,
+		PayloadType:   src.Body.Mode,
+		PayloadRaw:    src.Body.Raw,
+		PathVariables: p.parseRequestPathVariables(src),
+		PayloadParams: p.parseRequestPayloadParams(src),
+		Headers:       p.parseRequestHeaders(src),
+	}
+}
+
+func (p *CollectionV210Parser) parseEventScript(events []collectionV210Event, listen string) string {
+	for _, event := range events {
+		if event.Listen == listen {
 			return strings.Join(event.Script.Exec, "\n")
 		}
 	}
 	return ""
 }
 
-func (p *CollectionV210Parser) parseRequestPathVariables(item collectionV210Item) []KeyValuePair {
+func (p *CollectionV210Parser) parseRequestPathVariables(request collectionV210Request) []KeyValuePair {
 	pathVariables := make([]KeyValuePair, 0)
 
-	for _, variable := range item.Request.Url.Variable {
+	for _, variable := range request.Url.Variable {
 		pathVariables = append(pathVariables, KeyValuePair{
 			Name:        variable.Key,
 			Key:         variable.Key,
@@ -100,15 +109,15 @@ func (p *CollectionV210Parser) parseRequestPathVariables(item collectionV210Item
 	return pathVariables
 }
 
-func (p *CollectionV210Parser) parseRequestPayloadParams(item collectionV210Item) []KeyValuePair {
+func (p *CollectionV210Parser) parseRequestPayloadParams(request collectionV210Request) []KeyValuePair {
 	payloadParams := make([]KeyValuePair, 0)
 
 	keyValuePairCollection := make([]collectionV210KeyValuePair, 0)
-	switch item.Request.Body.Mode {
+	switch request.Body.Mode {
 	case "urlencoded":
-		keyValuePairCollection = item.Request.Body.UrlEncoded
+		keyValuePairCollection = request.Body.UrlEncoded
 	case "formdata":
-		keyValuePairCollection = item.Request.Body.FormData
+		keyValuePairCollection = request.Body.FormData
 	}
 
 	for _, pair := range keyValuePairCollection {
@@ -126,13 +135,10 @@ func (p *CollectionV210Parser) parseRequestPayloadParams(item collectionV210Item
 	return payloadParams
 }
 
-func (p *CollectionV210Parser) parseRequestHeaders(item collectionV210Item, options BuilderOptions) []KeyValuePair {
+func (p *CollectionV210Parser) parseRequestHeaders(request collectionV210Request) []KeyValuePair {
 	headers := make([]KeyValuePair, 0)
 
-	for _, header := range item.Request.Header {
-		if containsString(options.IgnoredRequestHeaders, header.Key) {
-			continue
-		}
+	for _, header := range request.Header {
 		headers = append(headers, KeyValuePair{
 			Name:        header.Key,
 			Key:         header.Key,
@@ -144,30 +150,33 @@ func (p *CollectionV210Parser) parseRequestHeaders(item collectionV210Item, opti
 	return headers
 }
 
-func (p *CollectionV210Parser) parseRequestResponses(item collectionV210Item, options BuilderOptions) []Response {
+func (p *CollectionV210Parser) parseRequestResponses(item collectionV210Item) []Response {
 	responses := make([]Response, 0)
 
 	for _, resp := range item.Response {
-		responses = append(responses, Response{
+		response := Response{
 			ID:         uuid.NewV4().String(),
 			Name:       resp.Name,
 			Body:       resp.Body,
 			Status:     resp.Status,
 			StatusCode: resp.Code,
-			Headers:    p.parseResponseHeaders(resp.Header, options),
-		})
+			Headers:    p.parseResponseHeaders(resp.Header),
+		}
+		if resp.OriginalRequest != nil {
+			originalRequest := p.buildRequest(*resp.OriginalRequest)
+			originalRequest.Name = resp.Name
+			response.OriginalRequest = &originalRequest
+		}
+		responses = append(responses, response)
 	}
 
 	return responses
 }
 
-func (p *CollectionV210Parser) parseResponseHeaders(headers []collectionV210KeyValuePair, options BuilderOptions) []KeyValuePair {
+func (p *CollectionV210Parser) parseResponseHeaders(headers []collectionV210KeyValuePair) []KeyValuePair {
 	parsedHeaders := make([]KeyValuePair, 0)
 
 	for _, header := range headers {
-		if containsString(options.IgnoredResponseHeaders, header.Key) {
-			continue
-		}
 		parsedHeaders = append(parsedHeaders, KeyValuePair{
 			Name:        header.Key,
 			Key:         header.Key,
@@ -177,12 +186,3 @@ func (p *CollectionV210Parser) parseResponseHeaders(headers []collectionV210KeyV
 	}
 	return parsedHeaders
 }
-
-func containsString(target []string, symbol string) bool {
-	for _, element := range target {
-		if element == symbol {
-			return true
-		}
-	}
-	return false
-}
@@ -0,0 +1,87 @@
+package postman
+
+import (
+	"testing"
+)
+
+func TestParseOriginalRequests(t *testing.T) {
+	// Given
+	parser := &CollectionV210Parser{}
+	contents := []byte(`{
+	"info": {"name": "Cats API"},
+	"item": [{
+		"name": "Create a new cat",
+		"request": {"method": "POST", "url": "http://{{domain}}/api/cats"},
+		"response": [{
+			"name": "default",
+			"originalRequest": {
+				"method": "POST",
+				"header": [{"key": "Content-Type", "value": "application/json"}],
+				"body": {"mode": "raw", "raw": "{\"name\": \"Tom\"}"},
+				"url": {"raw": "http://dockerhost:9999/api/cats"}
+			},
+			"code": 201
+		}]
+	}]
+}`)
+
+	// When
+	col, err := parser.Parse(contents, BuilderOptions{})
+
+	// Then
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	request := col.Requests[0]
+	if request.URL != "http://{{domain}}/api/cats" {
+		t.Errorf("URLs given as plain strings were not properly parsed, got %v", request.URL)
+	}
+	originalRequest := request.Responses[0].OriginalRequest
+	if originalRequest == nil {
+		t.Fatalf("Original request was not parsed")
+	}
+	if originalRequest.Method != "POST" || originalRequest.URL != "http://dockerhost:9999/api/cats" ||
+		originalRequest.PayloadRaw != `{"name": "Tom"}` || len(originalRequest.Headers) != 1 {
+		t.Errorf("Original request was not properly parsed, got %+v", originalRequest)
+	}
+}
+
+func TestParseEvents(t *testing.T) {
+	// Given
+	parser := &CollectionV210Parser{}
+	contents := []byte(`{
+	"info": {"name": "Cats API"},
+	"event": [
+		{"listen": "prerequest", "script": {"exec": ["// collection pre-request"]}},
+		{"listen": "test", "script": {"exec": ["// collection", "// tests"]}}
+	],
+	"item": [{
+		"name": "Cats",
+		"event": [{"listen": "test", "script": {"exec": ["// folder tests"]}}],
+		"item": [{
+			"name": "Get all cats",
+			"event": [{"listen": "prerequest", "script": {"exec": ["// request pre-request"]}}],
+			"request": {"method": "GET", "url": "http://{{domain}}/api/cats"}
+		}]
+	}]
+}`)
+
+	// When
+	col, err := parser.Parse(contents, BuilderOptions{})
+
+	// Then
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if col.PreRequestScript != "// collection pre-request" || col.Tests != "// collection\n// tests" {
+		t.Errorf("Collection events were not properly parsed, got %q and %q", col.PreRequestScript, col.Tests)
+	}
+	folder := col.Folders[0]
+	if folder.PreRequestScript != "" || folder.Tests != "// folder tests" {
+		t.Errorf("Folder events were not properly parsed, got %q and %q", folder.PreRequestScript, folder.Tests)
+	}
+	request := folder.Requests[0]
+	if request.PreRequestScript != "// request pre-request" || request.Tests != "" {
+		t.Errorf("Request events were not properly parsed, got %q and %q", request.PreRequestScript, request.Tests)
+	}
+}
@@ -0,0 +1,110 @@
+package postman
+
+import (
+	"encoding/json"
+	"io"
+	"strings"
+)
+
+const collectionV210Schema = "https://schema.getpostman.com/json/collection/v2.1.0/collection.json"
+
+// CollectionV210Writer exports collections in the Postman v2.1 format.
+type CollectionV210Writer struct{}
+
+func (wr *CollectionV210Writer) Write(w io.Writer, collection Collection) error {
+	dst := collectionV210{}
+	dst.Info.Name = collection.Name
+	dst.Info.Description = collection.Description
+	dst.Info.Schema = collectionV210Schema
+	dst.Event = wr.buildEvents(collection.PreRequestScript, collection.Tests)
+	dst.Item = wr.buildItems(collection.Requests, collection.Folders)
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "\t")
+	return encoder.Encode(dst)
+}
+
+func (wr *CollectionV210Writer) buildItems(requests []Request, folders []Folder) []collectionV210Item {
+	items := make([]collectionV210Item, 0, len(requests)+len(folders))
+	for _, folder := range folders {
+		items = append(items, collectionV210Item{
+			Name:        folder.Name,
+			Description: folder.Description,
+			Event:       wr.buildEvents(folder.PreRequestScript, folder.Tests),
+			Item:        wr.buildItems(folder.Requests, folder.Folders),
+		})
+	}
+	for _, request := range requests {
+		src := wr.buildRequest(request)
+		items = append(items, collectionV210Item{
+			Name:     request.Name,
+			Event:    wr.buildEvents(request.PreRequestScript, request.Tests),
+			Request:  &src,
+			Response: wr.buildResponses(request.Responses),
+		})
+	}
+	return items
+}
+
+func (wr *CollectionV210Writer) buildEvents(preRequestScript, tests string) []collectionV210Event {
+	events := make([]collectionV210Event, 0)
+	for _, script := range []struct{ listen, source string }{{"prerequest", preRequestScript}, {"test", tests}} {
+		if script.source == "" {
+			continue
+		}
+		event := collectionV210Event{Listen: script.listen}
+		event.Script.Type = "text/javascript"
+		event.Script.Exec = strings.Split(script.source, "\n")
+		events = append(events, event)
+	}
+	return events
+}
+
+func (wr *CollectionV210Writer) buildRequest(request Request) collectionV210Request {
+	dst := collectionV210Request{
+		Method:      request.Method,
+		Header:      wr.buildKeyValuePairs(request.Headers),
+		Url:         collectionV210Url{Raw: request.URL, Variable: wr.buildKeyValuePairs(request.PathVariables)},
+		Description: request.Description,
+	}
+	dst.Body.Mode = request.PayloadType
+	dst.Body.Raw = request.PayloadRaw
+	switch request.PayloadType {
+	case "urlencoded":
+		dst.Body.UrlEncoded = wr.buildKeyValuePairs(request.PayloadParams)
+	case "formdata":
+		dst.Body.FormData = wr.buildKeyValuePairs(request.PayloadParams)
+	}
+	return dst
+}
+
+func (wr *CollectionV210Writer) buildResponses(responses []Response) []collectionV210Response {
+	dst := make([]collectionV210Response, 0, len(responses))
+	for _, response := range responses {
+		resp := collectionV210Response{
+			Name:   response.Name,
+			Status: response.Status,
+			Code:   response.StatusCode,
+			Header: wr.buildKeyValuePairs(response.Headers),
+			Body:   response.Body,
+		}
+		if response.OriginalRequest != nil {
+			originalRequest := wr.buildRequest(*response.OriginalRequest)
+			resp.OriginalRequest = &originalRequest
+		}
+		dst = append(dst, resp)
+	}
+	return dst
+}
+
+func (wr *CollectionV210Writer) buildKeyValuePairs(pairs []KeyValuePair) []collectionV210KeyValuePair {
+	dst := make([]collectionV210KeyValuePair, 0, len(pairs))
+	for _, pair := range pairs {
+		dst = append(dst, collectionV210KeyValuePair{
+			Key:         pair.Key,
+			Value:       pair.Value,
+			Description: pair.Description,
+		})
+	}
+	return dst
+}
@@ -0,0 +1,76 @@
+package postman
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestWriteCollectionV210(t *testing.T) {
+	// Given
+	writer := &CollectionV210Writer{}
+	collection := Collection{
+		Name:             "Cats API",
+		PreRequestScript: "// collection pre-request",
+		Folders: []Folder{{
+			Name:  "cats",
+			Tests: "// folder\n// tests",
+			Requests: []Request{{
+				Name:          "GET /cats/:id",
+				Method:        "GET",
+				URL:           "http://localhost:8080/cats/:id",
+				PathVariables: []KeyValuePair{{Key: "id", Value: "42"}},
+				Headers:       []KeyValuePair{{Key: "Accept", Value: "application/json"}},
+				Responses: []Response{{
+					Name:       "200 OK",
+					Status:     "OK",
+					StatusCode: 200,
+					Body:       `{"name":"Tom"}`,
+					OriginalRequest: &Request{
+						Method: "GET",
+						URL:    "http://localhost:8080/cats/42?full=true",
+					},
+				}},
+			}},
+		}},
+		Requests: []Request{{
+			Name:          "POST /login",
+			Method:        "POST",
+			URL:           "http://localhost:8080/login",
+			PayloadType:   "urlencoded",
+			PayloadParams: []KeyValuePair{{Key: "user", Value: "tom"}},
+		}},
+	}
+
+	// When
+	out := new(bytes.Buffer)
+	err := writer.Write(out, collection)
+
+	// Then
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	parsed, err := (&CollectionV210Parser{}).Parse(out.Bytes(), BuilderOptions{})
+	if err != nil {
+		t.Fatalf("The written collection could not be parsed: %v", err)
+	}
+	if parsed.Name != "Cats API" || len(parsed.Folders) != 1 || len(parsed.Requests) != 1 {
+		t.Fatalf("The collection was not properly written, got %+v", parsed)
+	}
+	request := parsed.Folders[0].Requests[0]
+	if request.URL != "http://localhost:8080/cats/:id" || len(request.PathVariables) != 1 ||
+		request.PathVariables[0].Value != "42" || len(request.Headers) != 1 {
+		t.Errorf("The request was not properly written, got %+v", request)
+	}
+	response := request.Responses[0]
+	if response.StatusCode != 200 || response.Body != `{"name":"Tom"}` || response.OriginalRequest == nil ||
+		response.OriginalRequest.URL != "http://localhost:8080/cats/42?full=true" {
+		t.Errorf("The response was not properly written, got %+v", response)
+	}
+	if parsed.PreRequestScript != "// collection pre-request" || parsed.Folders[0].Tests != "// folder\n// tests" {
+		t.Errorf("The scripts were not properly written, got %q and %q", parsed.PreRequestScript, parsed.Folders[0].Tests)
+	}
+	login := parsed.Requests[0]
+	if login.PayloadType != "urlencoded" || len(login.PayloadParams) != 1 || login.PayloadParams[0].Value != "tom" {
+		t.Errorf("The payload was not properly written, got %+v", login)
+	}
+}
@@ -0,0 +1,183 @@
+package postman
+
+import (
+	"regexp"
+	"strings"
+)
+
+// pathFilter selects the folders and requests of a collection by their path, such as "Admin/Users/Get all users".
+type pathFilter struct {
+	include []*regexp.Regexp
+	exclude []*regexp.Regexp
+}
+
+func newPathFilter(include, exclude []string) pathFilter {
+	filter := pathFilter{}
+	for _, pattern := range include {
+		filter.include = append(filter.include, globToRegexp(pattern))
+	}
+	for _, pattern := range exclude {
+		filter.exclude = append(filter.exclude, globToRegexp(pattern))
+	}
+	return filter
+}
+
+// globToRegexp converts a glob pattern, in which "*" matches any part of a name and "**" any part of a path.
+func globToRegexp(pattern string) *regexp.Regexp {
+	var expr strings.Builder
+	expr.WriteString("^")
+	for i := 0; i < len(pattern); i++ {
+		switch {
+		case strings.HasPrefix(pattern[i:], "**"):
+			expr.WriteString(".*")
+			i++
+		case pattern[i] == '*':
+			expr.WriteString("[^/]*")
+		case pattern[i] == '?':
+			expr.WriteString("[^/]")
+		default:
+			expr.WriteString(regexp.QuoteMeta(pattern[i : i+1]))
+		}
+	}
+	expr.WriteString("$")
+	return regexp.MustCompile(expr.String())
+}
+
+func (f pathFilter) active() bool {
+	return len(f.include) > 0 || len(f.exclude) > 0
+}
+
+func matchAny(patterns []*regexp.Regexp, path string) bool {
+	for _, pattern := range patterns {
+		if pattern.MatchString(path) {
+			return true
+		}
+	}
+	return false
+}
+
+// apply removes the folders and requests that are excluded, or that are not included when there are include patterns.
+// The content of a matching folder matches as well, and the folders left empty are removed.
+func (f pathFilter) apply(col *Collection) {
+	included := len(f.include) == 0
+	col.Requests = f.filterRequests(col.Requests, "", included)
+	col.Folders = f.filterFolders(col.Folders, "", included)
+}
+
+func (f pathFilter) filterFolders(folders []Folder, parent string, included bool) []Folder {
+	filtered := make([]Folder, 0, len(folders))
+	for _, folder := range folders {
+		path := joinPath(parent, folder.Name)
+		if matchAny(f.exclude, path) {
+			continue
+		}
+		folderIncluded := included || matchAny(f.include, path)
+		folder.Requests = f.filterRequests(folder.Requests, path, folderIncluded)
+		folder.Folders = f.filterFolders(folder.Folders, path, folderIncluded)
+		if len(folder.Requests) == 0 && len(folder.Folders) == 0 {
+			continue
+		}
+		filtered = append(filtered, folder)
+	}
+	return filtered
+}
+
+func (f pathFilter) filterRequests(requests []Request, parent string, included bool) []Request {
+	filtered := make([]Request, 0, len(requests))
+	for _, request := range requests {
+		path := joinPath(parent, request.Name)
+		if matchAny(f.exclude, path) || !(included || matchAny(f.include, path)) {
+			continue
+		}
+		filtered = append(filtered, request)
+	}
+	return filtered
+}
+
+func joinPath(parent, name string) string {
+	if parent == "" {
+		return name
+	}
+	return parent + "/" + name
+}
+
+// tagFilter selects the requests by the tags of their metadata and of the metadata of their folders.
+// A tag starting with "!" excludes the requests that have it.
+type tagFilter struct {
+	include []string
+	exclude []string
+}
+
+func newTagFilter(tags []string) tagFilter {
+	filter := tagFilter{}
+	for _, tag := range tags {
+		if strings.HasPrefix(tag, "!") {
+			filter.exclude = append(filter.exclude, tag[1:])
+		} else {
+			filter.include = append(filter.include, tag)
+		}
+	}
+	return filter
+}
+
+func (f tagFilter) active() bool {
+	return len(f.include) > 0 || len(f.exclude) > 0
+}
+
+// apply removes the requests that do not match the tags, and the folders left empty.
+func (f tagFilter) apply(col *Collection) {
+	metas := []Meta{col.Meta}
+	col.Requests = f.filterRequests(col.Requests, metas)
+	col.Folders = f.filterFolders(col.Folders, metas)
+}
+
+func (f tagFilter) filterFolders(folders []Folder, parentMetas []Meta) []Folder {
+	filtered := make([]Folder, 0, len(folders))
+	for _, folder := range folders {
+		metas := append(append(make([]Meta, 0, len(parentMetas)+1), parentMetas...), folder.Meta)
+		folder.Requests = f.filterRequests(folder.Requests, metas)
+		folder.Folders = f.filterFolders(folder.Folders, metas)
+		if len(folder.Requests) == 0 && len(folder.Folders) == 0 {
+			continue
+		}
+		filtered = append(filtered, folder)
+	}
+	return filtered
+}
+
+func (f tagFilter) filterRequests(requests []Request, parentMetas []Meta) []Request {
+	filtered := make([]Request, 0, len(requests))
+	for _, request := range requests {
+		metas := append(append(make([]Meta, 0, len(parentMetas)+1), parentMetas...), request.Meta)
+		if f.matches(metas) {
+			filtered = append(filtered, request)
+		}
+	}
+	return filtered
+}
+
+func (f tagFilter) matches(metas []Meta) bool {
+	for _, tag := range f.exclude {
+		if hasTag(metas, tag) {
+			return false
+		}
+	}
+	if len(f.include) == 0 {
+		return true
+	}
+	for _, tag := range f.include {
+		if hasTag(metas, tag) {
+			return true
+		}
+	}
+	return false
+}
+
+func hasTag(metas []Meta, tag string) bool {
+	for _, meta := range metas {
+		if meta.HasTag(tag) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,113 @@
+package postman
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestPathFilter(t *testing.T) {
+	col := Collection{
+		Requests: []Request{{Name: "Health check"}},
+		Folders: []Folder{
+			{Name: "Cats", Requests: []Request{{Name: "Get all cats"}, {Name: "Debug cats"}}},
+			{Name: "Admin", Folders: []Folder{
+				{Name: "Users", Requests: []Request{{Name: "Get all users"}}},
+			}},
+			{Name: "Dogs", Requests: []Request{{Name: "Debug dogs"}}},
+		},
+	}
+
+	testCases := []struct {
+		include, exclude []string
+		expected         []string
+	}{
+		{
+			expected: []string{"Health check", "Cats/Get all cats", "Cats/Debug cats", "Admin/Users/Get all users", "Dogs/Debug dogs"},
+		},
+		{
+			exclude:  []string{"Admin/**", "*/Debug *"},
+			expected: []string{"Health check", "Cats/Get all cats"},
+		},
+		{
+			include:  []string{"Admin"},
+			expected: []string{"Admin/Users/Get all users"},
+		},
+		{
+			include:  []string{"**/Get all *"},
+			exclude:  []string{"Cats"},
+			expected: []string{"Admin/Users/Get all users"},
+		},
+		{
+			include:  []string{"Cats/Get all cat?", "Health check"},
+			expected: []string{"Health check", "Cats/Get all cats"},
+		},
+	}
+
+	for _, tc := range testCases {
+		// Given
+		filtered := col
+		filter := newPathFilter(tc.include, tc.exclude)
+
+		// When
+		filter.apply(&filtered)
+
+		// Then
+		paths := requestPaths(filtered.Requests, filtered.Folders, "")
+		if !reflect.DeepEqual(paths, tc.expected) {
+			t.Errorf("With include %q and exclude %q, expected %q, got %q", tc.include, tc.exclude, tc.expected, paths)
+		}
+		for _, folder := range filtered.Folders {
+			if len(folder.Requests) == 0 && len(folder.Folders) == 0 {
+				t.Errorf("With include %q and exclude %q, the empty folder %v was not pruned", tc.include, tc.exclude, folder.Name)
+			}
+		}
+	}
+}
+
+func requestPaths(requests []Request, folders []Folder, parent string) []string {
+	paths := make([]string, 0)
+	for _, request := range requests {
+		paths = append(paths, joinPath(parent, request.Name))
+	}
+	for _, folder := range folders {
+		paths = append(paths, requestPaths(folder.Requests, folder.Folders, joinPath(parent, folder.Name))...)
+	}
+	return paths
+}
+
+func TestTagFilter(t *testing.T) {
+	col := Collection{
+		Requests: []Request{{Name: "Health check"}},
+		Folders: []Folder{
+			{Name: "Cats", Meta: Meta{"tags": []interface{}{"public"}}, Requests: []Request{
+				{Name: "Get all cats"},
+				{Name: "Debug cats", Meta: Meta{"internal": true}},
+			}},
+			{Name: "Admin", Meta: Meta{"internal": true}, Requests: []Request{{Name: "Get all users", Meta: Meta{"tags": "partner"}}}},
+			{Name: "Dogs", Requests: []Request{{Name: "Get all dogs", Meta: Meta{"tags": "partner, beta"}}}},
+		},
+	}
+
+	testCases := []struct {
+		tags     []string
+		expected []string
+	}{
+		{tags: []string{"public"}, expected: []string{"Cats/Get all cats", "Cats/Debug cats"}},
+		{tags: []string{"public", "partner", "!internal"}, expected: []string{"Cats/Get all cats", "Dogs/Get all dogs"}},
+		{tags: []string{"!internal", "!beta"}, expected: []string{"Health check", "Cats/Get all cats"}},
+	}
+
+	for _, tc := range testCases {
+		// Given
+		filtered := col
+
+		// When
+		newTagFilter(tc.tags).apply(&filtered)
+
+		// Then
+		paths := requestPaths(filtered.Requests, filtered.Folders, "")
+		if !reflect.DeepEqual(paths, tc.expected) {
+			t.Errorf("With tags %q, expected %q, got %q", tc.tags, tc.expected, paths)
+		}
+	}
+}
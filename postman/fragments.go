@@ -0,0 +1,61 @@
+package postman
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"time"
+
+	"github.com/robertkrimen/otto"
+	"github.com/robertkrimen/otto/parser"
+)
+
+const (
+	defaultScriptTimeout = 5 * time.Second
+	fragmentStartTag     = "/*[[start postmanerator]]*/"
+	fragmentEndTag       = "/*[[end postmanerator]]*/"
+
+	structuresPrelude = `
+var APIStructures = {};
+var console = {log: function () {}, info: function () {}, warn: function () {}, error: function () {}};
+`
+	structuresEpilogue = `
+if (typeof populateNewAPIStructures === 'function') {
+    populateNewAPIStructures();
+}`
+)
+
+var stackFrameLine = regexp.MustCompile(`<anonymous>:(\d+):\d+`)
+
+// codeFragment is a piece of Javascript delimited by the postmanerator tags in a test script.
+type codeFragment struct {
+	owner  string
+	line   int
+	source string
+}
+
+// errorf builds an error located at the given line of the fragment, or at its first line if the line is unknown.
+func (frag codeFragment) errorf(line int, format string, a ...interface{}) error {
+	if line < 1 {
+		line = 1
+	}
+	return fmt.Errorf("%v, line %d: %v", frag.owner, frag.line+line-1, fmt.Sprintf(format, a...))
+}
+
+// describeScriptError returns the line an otto error occurred at, or 0 if it is unknown, and its message.
+func describeScriptError(err error) (int, string) {
+	switch e := err.(type) {
+	case *otto.Error:
+		if m := stackFrameLine.FindStringSubmatch(e.String()); m != nil {
+			line, _ := strconv.Atoi(m[1])
+			return line, e.Error()
+		}
+	case parser.ErrorList:
+		if len(e) > 0 {
+			return e[0].Position.Line, e[0].Message
+		}
+	case *parser.Error:
+		return e.Position.Line, e.Message
+	}
+	return 0, err.Error()
+}
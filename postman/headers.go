@@ -0,0 +1,152 @@
+package postman
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Metadata entries scoping header rules to the collection, to a folder and its content, or to a request.
+const (
+	metaIgnoredRequestHeaders  = "ignored-request-headers"
+	metaIgnoredResponseHeaders = "ignored-response-headers"
+	metaAllowedRequestHeaders  = "allowed-request-headers"
+	metaAllowedResponseHeaders = "allowed-response-headers"
+)
+
+// headerRules selects the headers to document. A header is kept when it matches an allowed pattern, or when there is
+// none, and no ignored pattern.
+type headerRules struct {
+	ignored []*regexp.Regexp
+	allowed []*regexp.Regexp
+}
+
+// headerFilter holds the rules of the request headers, including the ones of the original requests of the responses,
+// and the rules of the response headers.
+type headerFilter struct {
+	request  headerRules
+	response headerRules
+}
+
+// compileHeaderPatterns compiles case insensitive header patterns, which are names, globs such as "X-Amz-*", or
+// regular expressions between slashes such as "/^x-(amz|goog)-/".
+func compileHeaderPatterns(patterns []string) ([]*regexp.Regexp, error) {
+	compiled := make([]*regexp.Regexp, 0, len(patterns))
+	for _, pattern := range patterns {
+		pattern = strings.TrimSpace(pattern)
+		if pattern == "" {
+			continue
+		}
+		if len(pattern) > 2 && strings.HasPrefix(pattern, "/") && strings.HasSuffix(pattern, "/") {
+			expr := pattern[1 : len(pattern)-1]
+			if _, err := regexp.Compile(expr); err != nil {
+				return nil, fmt.Errorf("invalid header pattern %v: %v", pattern, err)
+			}
+			compiled = append(compiled, regexp.MustCompile("(?i)"+expr))
+			continue
+		}
+		compiled = append(compiled, regexp.MustCompile("(?i)"+globToRegexp(pattern).String()))
+	}
+	return compiled, nil
+}
+
+func newHeaderFilter(options BuilderOptions) (f headerFilter, err error) {
+	if f.request.ignored, err = compileHeaderPatterns(options.IgnoredRequestHeaders); err != nil {
+		return
+	}
+	if f.request.allowed, err = compileHeaderPatterns(options.AllowedRequestHeaders); err != nil {
+		return
+	}
+	if f.response.ignored, err = compileHeaderPatterns(options.IgnoredResponseHeaders); err != nil {
+		return
+	}
+	f.response.allowed, err = compileHeaderPatterns(options.AllowedResponseHeaders)
+	return
+}
+
+// scoped adds the rules found in metadata: ignored headers add up to the inherited ones, while allowed headers
+// replace them.
+func (r headerRules) scoped(meta Meta, ignoredKey, allowedKey string) (headerRules, error) {
+	ignored, err := compileHeaderPatterns(meta.List(ignoredKey))
+	if err != nil {
+		return r, err
+	}
+	allowed, err := compileHeaderPatterns(meta.List(allowedKey))
+	if err != nil {
+		return r, err
+	}
+	if len(ignored) > 0 {
+		r.ignored = append(append([]*regexp.Regexp{}, r.ignored...), ignored...)
+	}
+	if len(allowed) > 0 {
+		r.allowed = allowed
+	}
+	return r, nil
+}
+
+func (r headerRules) keep(name string) bool {
+	if len(r.allowed) > 0 && !matchAny(r.allowed, name) {
+		return false
+	}
+	return !matchAny(r.ignored, name)
+}
+
+func (r headerRules) apply(headers []KeyValuePair) []KeyValuePair {
+	if len(r.ignored) == 0 && len(r.allowed) == 0 {
+		return headers
+	}
+	kept := make([]KeyValuePair, 0, len(headers))
+	for _, header := range headers {
+		if r.keep(header.Key) {
+			kept = append(kept, header)
+		}
+	}
+	return kept
+}
+
+// filterHeaders removes the ignored headers from the requests and from the responses of the collection.
+func (c *CollectionBuilder) filterHeaders(col *Collection, options BuilderOptions) error {
+	filter, err := newHeaderFilter(options)
+	if err != nil {
+		return err
+	}
+	root := Folder{Name: col.Name, Meta: col.Meta, Requests: col.Requests, Folders: col.Folders}
+	c.filterFolderHeaders(col, &root, filter)
+	col.Requests = root.Requests
+	col.Folders = root.Folders
+	return nil
+}
+
+func (c *CollectionBuilder) filterFolderHeaders(col *Collection, folder *Folder, filter headerFilter) {
+	filter = c.scopedHeaderFilter(col, folder.Name, folder.Meta, filter)
+	for i := range folder.Requests {
+		request := &folder.Requests[i]
+		requestFilter := c.scopedHeaderFilter(col, request.Name, request.Meta, filter)
+		request.Headers = requestFilter.request.apply(request.Headers)
+		for j := range request.Responses {
+			response := &request.Responses[j]
+			response.Headers = requestFilter.response.apply(response.Headers)
+			if response.OriginalRequest != nil {
+				response.OriginalRequest.Headers = requestFilter.request.apply(response.OriginalRequest.Headers)
+			}
+		}
+	}
+	for i := range folder.Folders {
+		c.filterFolderHeaders(col, &folder.Folders[i], filter)
+	}
+}
+
+func (c *CollectionBuilder) scopedHeaderFilter(col *Collection, owner string, meta Meta, filter headerFilter) headerFilter {
+	if len(meta) == 0 {
+		return filter
+	}
+	request, err := filter.request.scoped(meta, metaIgnoredRequestHeaders, metaAllowedRequestHeaders)
+	if err != nil {
+		col.Warnings = append(col.Warnings, fmt.Sprintf("%v: %v", owner, err))
+	}
+	response, err := filter.response.scoped(meta, metaIgnoredResponseHeaders, metaAllowedResponseHeaders)
+	if err != nil {
+		col.Warnings = append(col.Warnings, fmt.Sprintf("%v: %v", owner, err))
+	}
+	return headerFilter{request: request, response: response}
+}
@@ -0,0 +1,118 @@
+package postman
+
+import (
+	"reflect"
+	"testing"
+)
+
+func headerNames(headers []KeyValuePair) []string {
+	names := make([]string, 0, len(headers))
+	for _, header := range headers {
+		names = append(names, header.Key)
+	}
+	return names
+}
+
+func TestFilterHeaders(t *testing.T) {
+	newHeaders := func() []KeyValuePair {
+		return []KeyValuePair{{Key: "content-type"}, {Key: "X-Amz-Date"}, {Key: "X-Amz-Request-Id"}, {Key: "CF-Ray"}, {Key: "Accept"}}
+	}
+
+	testCases := []struct {
+		options  BuilderOptions
+		expected []string
+	}{
+		{options: BuilderOptions{}, expected: []string{"content-type", "X-Amz-Date", "X-Amz-Request-Id", "CF-Ray", "Accept"}},
+		{options: BuilderOptions{IgnoredRequestHeaders: []string{"Content-Type"}}, expected: []string{"X-Amz-Date", "X-Amz-Request-Id", "CF-Ray", "Accept"}},
+		{options: BuilderOptions{IgnoredRequestHeaders: []string{"x-amz-*"}}, expected: []string{"content-type", "CF-Ray", "Accept"}},
+		{options: BuilderOptions{IgnoredRequestHeaders: []string{"/^(x-amz|cf)-/"}}, expected: []string{"content-type", "Accept"}},
+		{options: BuilderOptions{AllowedRequestHeaders: []string{"Content-Type", "Accept"}}, expected: []string{"content-type", "Accept"}},
+		{options: BuilderOptions{AllowedRequestHeaders: []string{"X-Amz-*"}, IgnoredRequestHeaders: []string{"X-Amz-Date"}}, expected: []string{"X-Amz-Request-Id"}},
+	}
+
+	for _, tc := range testCases {
+		// Given
+		builder := &CollectionBuilder{}
+		col := Collection{Requests: []Request{{
+			Name:      "Get all cats",
+			Headers:   newHeaders(),
+			Responses: []Response{{Headers: newHeaders(), OriginalRequest: &Request{Headers: newHeaders()}}},
+		}}}
+
+		// When
+		err := builder.filterHeaders(&col, tc.options)
+
+		// Then
+		if err != nil {
+			t.Errorf("Unexpected error with options %+v: %v", tc.options, err)
+		}
+		request := col.Requests[0]
+		if names := headerNames(request.Headers); !reflect.DeepEqual(names, tc.expected) {
+			t.Errorf("With options %+v, expected request headers %q, got %q", tc.options, tc.expected, names)
+		}
+		if names := headerNames(request.Responses[0].OriginalRequest.Headers); !reflect.DeepEqual(names, tc.expected) {
+			t.Errorf("With options %+v, expected original request headers %q, got %q", tc.options, tc.expected, names)
+		}
+		if names := headerNames(request.Responses[0].Headers); len(names) != 5 {
+			t.Errorf("With options %+v, expected the response headers to be kept, got %q", tc.options, names)
+		}
+	}
+}
+
+func TestFilterHeadersScopedToFolders(t *testing.T) {
+	// Given
+	builder := &CollectionBuilder{}
+	headers := []KeyValuePair{{Key: "Content-Type"}, {Key: "X-Amz-Date"}, {Key: "X-Debug"}}
+	col := Collection{
+		Meta: Meta{"ignored-response-headers": "X-Debug"},
+		Requests: []Request{{
+			Name:      "Get all cats",
+			Responses: []Response{{Headers: headers}},
+		}},
+		Folders: []Folder{{
+			Name: "Uploads",
+			Meta: Meta{"ignored-response-headers": []interface{}{"x-amz-*"}},
+			Requests: []Request{
+				{Name: "Upload a photo", Responses: []Response{{Headers: headers}}},
+				{Name: "Get a photo", Meta: Meta{"allowed-response-headers": "Content-*"}, Responses: []Response{{Headers: headers}}},
+			},
+		}},
+	}
+
+	// When
+	err := builder.filterHeaders(&col, BuilderOptions{})
+
+	// Then
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	expected := map[string][]string{
+		"Get all cats":   {"Content-Type", "X-Amz-Date"},
+		"Upload a photo": {"Content-Type"},
+		"Get a photo":    {"Content-Type"},
+	}
+	requests := append(append([]Request{}, col.Requests...), col.Folders[0].Requests...)
+	for _, request := range requests {
+		if names := headerNames(request.Responses[0].Headers); !reflect.DeepEqual(names, expected[request.Name]) {
+			t.Errorf("Expected the response headers of %v to be %q, got %q", request.Name, expected[request.Name], names)
+		}
+	}
+}
+
+func TestFilterHeadersInvalidPattern(t *testing.T) {
+	// Given
+	builder := &CollectionBuilder{}
+	col := Collection{Folders: []Folder{{Name: "Cats", Meta: Meta{"ignored-request-headers": "/x-(/"}}}}
+
+	// When
+	err := builder.filterHeaders(&col, BuilderOptions{IgnoredResponseHeaders: []string{"/[/"}})
+	errFolder := builder.filterHeaders(&col, BuilderOptions{})
+
+	// Then
+	if err == nil || err.Error() != "invalid header pattern /[/: error parsing regexp: missing closing ]: `[`" {
+		t.Errorf("Expected an invalid pattern error, got %v", err)
+	}
+	if errFolder != nil || len(col.Warnings) != 1 || col.Warnings[0] != "Cats: invalid header pattern /x-(/: error parsing regexp: missing closing ): `x-(`" {
+		t.Errorf("Expected an invalid pattern warning, got %v and %q", errFolder, col.Warnings)
+	}
+}
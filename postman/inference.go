@@ -0,0 +1,298 @@
+package postman
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// shape accumulates the JSON values found at the same place in several examples.
+type shape struct {
+	types    []string
+	nullable bool
+	// count is the number of values merged in this shape, and objects the number of these values that are objects.
+	count   int
+	objects int
+	example interface{}
+	keys    []string
+	fields  map[string]*shape
+	items   *shape
+}
+
+func (s *shape) merge(value interface{}) {
+	s.count++
+	switch v := value.(type) {
+	case nil:
+		s.nullable = true
+	case object:
+		s.addType("object")
+		s.objects++
+		if s.fields == nil {
+			s.fields = map[string]*shape{}
+		}
+		for _, key := range v.keys {
+			field, ok := s.fields[key]
+			if !ok {
+				field = &shape{}
+				s.fields[key] = field
+				s.keys = append(s.keys, key)
+			}
+			field.merge(v.values[key])
+		}
+	case []interface{}:
+		s.addType("array")
+		for _, item := range v {
+			if s.items == nil {
+				s.items = &shape{}
+			}
+			s.items.merge(item)
+		}
+	case json.Number:
+		if _, err := v.Int64(); err == nil {
+			s.addType("integer")
+		} else {
+			s.addType("number")
+		}
+		s.setExample(jsonNumber(v))
+	case string:
+		s.addType("string")
+		s.setExample(v)
+	case bool:
+		s.addType("boolean")
+		s.setExample(v)
+	}
+}
+
+func (s *shape) addType(t string) {
+	for _, existing := range s.types {
+		if existing == t {
+			return
+		}
+	}
+	s.types = append(s.types, t)
+}
+
+func (s *shape) setExample(value interface{}) {
+	if s.example == nil {
+		s.example = value
+	}
+}
+
+// typeName returns the JSON type of the shape, integers being numbers when the examples mix both.
+func (s *shape) typeName() string {
+	types := make([]string, 0, len(s.types))
+	hasNumber := false
+	for _, t := range s.types {
+		hasNumber = hasNumber || t == "number"
+	}
+	for _, t := range s.types {
+		if t != "integer" || !hasNumber {
+			types = append(types, t)
+		}
+	}
+	return strings.Join(types, "|")
+}
+
+func (s *shape) structureFields() []StructureFieldDefinition {
+	fields := make([]StructureFieldDefinition, 0, len(s.keys))
+	for _, key := range s.keys {
+		field := s.fields[key].field()
+		field.Name = key
+		field.Required = s.fields[key].count == s.objects
+		fields = append(fields, field)
+	}
+	return fields
+}
+
+func (s *shape) field() StructureFieldDefinition {
+	field := StructureFieldDefinition{Type: s.typeName(), Nullable: s.nullable, Example: s.example}
+	if s.objects > 0 {
+		field.Fields = s.structureFields()
+	}
+	if s.items != nil {
+		items := s.items.field()
+		field.Items = &items
+	}
+	return field
+}
+
+// inferStructures adds the structures inferred from the JSON bodies of the examples of each request,
+// unless a structure with the same name is already defined.
+func (c *CollectionBuilder) inferStructures(col *Collection) {
+	owners := inferenceOwners(col)
+	structures := make([]StructureDefinition, 0)
+	walkRequests(col, func(_ []*Folder, request *Request) {
+		structures = append(structures, c.inferRequestStructures(owners[request], *request)...)
+	})
+	for _, structure := range structures {
+		if col.FindStructure(structure.Name) == nil {
+			col.Structures = append(col.Structures, structure)
+		}
+	}
+}
+
+// inferRequestStructures infers the structure of the request body from the request and from the original requests
+// of its responses, and the structure of the response body for each status code.
+func (c *CollectionBuilder) inferRequestStructures(owner string, request Request) []StructureDefinition {
+	requestBodies := []string{request.PayloadRaw}
+	codes := make([]int, 0)
+	responseBodies := map[int][]string{}
+	for _, response := range request.Responses {
+		if response.OriginalRequest != nil {
+			requestBodies = append(requestBodies, response.OriginalRequest.PayloadRaw)
+		}
+		if _, ok := responseBodies[response.StatusCode]; !ok {
+			codes = append(codes, response.StatusCode)
+		}
+		responseBodies[response.StatusCode] = append(responseBodies[response.StatusCode], response.Body)
+	}
+
+	structures := make([]StructureDefinition, 0)
+	if structure, ok := inferStructure(inferredRequestStructureName(owner), requestBodies); ok {
+		structures = append(structures, structure)
+	}
+	for _, code := range codes {
+		if structure, ok := inferStructure(inferredResponseStructureName(owner, code), responseBodies[code]); ok {
+			structures = append(structures, structure)
+		}
+	}
+	return structures
+}
+
+// inferenceOwners returns how the requests are named in the names of their inferred structures: by their name, or by
+// their path when several requests have the same name. Requests that still have the same path get a number.
+func inferenceOwners(col *Collection) map[*Request]string {
+	counts := make(map[string]int)
+	walkRequests(col, func(_ []*Folder, request *Request) {
+		counts[request.Name]++
+	})
+
+	owners := make(map[*Request]string)
+	used := make(map[string]bool)
+	walkRequests(col, func(path []*Folder, request *Request) {
+		owner := request.Name
+		if counts[request.Name] > 1 {
+			owner = ""
+			for _, folder := range path {
+				owner = joinPath(owner, folder.Name)
+			}
+			owner = joinPath(owner, request.Name)
+		}
+		candidate := owner
+		for i := 2; used[candidate]; i++ {
+			candidate = fmt.Sprintf("%v %d", owner, i)
+		}
+		used[candidate] = true
+		owners[request] = candidate
+	})
+	return owners
+}
+
+// inferredRequestStructureName returns the name of the structure inferred from the request bodies of a request.
+func inferredRequestStructureName(owner string) string {
+	return fmt.Sprintf("%v (request)", owner)
+}
+
+// inferredResponseStructureName returns the name of the structure inferred from the responses of a request
+// with the given status code.
+func inferredResponseStructureName(owner string, statusCode int) string {
+	if statusCode == 0 {
+		return fmt.Sprintf("%v (response)", owner)
+	}
+	return fmt.Sprintf("%v (%d response)", owner, statusCode)
+}
+
+// inferStructure merges the JSON bodies that are objects, or arrays of objects. Other bodies are ignored.
+func inferStructure(name string, bodies []string) (StructureDefinition, bool) {
+	body := &shape{}
+	examples := 0
+	for _, raw := range bodies {
+		value, err := decodeJSONOrdered([]byte(raw))
+		if err != nil {
+			continue
+		}
+		if list, ok := value.([]interface{}); ok {
+			for _, item := range list {
+				if _, ok := item.(object); ok {
+					body.merge(item)
+				}
+			}
+			examples++
+		} else if _, ok := value.(object); ok {
+			body.merge(value)
+			examples++
+		}
+	}
+	if body.objects == 0 {
+		return StructureDefinition{}, false
+	}
+	return StructureDefinition{
+		Name:        name,
+		Description: fmt.Sprintf("Inferred from %d example(s).", examples),
+		Fields:      body.structureFields(),
+		Inferred:    true,
+	}, true
+}
+
+// decodeJSONOrdered decodes a JSON document into objects that remember the order of their keys.
+func decodeJSONOrdered(contents []byte) (interface{}, error) {
+	decoder := json.NewDecoder(bytes.NewReader(contents))
+	decoder.UseNumber()
+	value, err := decodeJSONValue(decoder)
+	if err != nil {
+		return nil, err
+	}
+	if decoder.More() {
+		return nil, fmt.Errorf("unexpected data after the JSON value")
+	}
+	return value, nil
+}
+
+func decodeJSONValue(decoder *json.Decoder) (interface{}, error) {
+	token, err := decoder.Token()
+	if err != nil {
+		return nil, err
+	}
+	switch token {
+	case json.Delim('{'):
+		o := object{keys: make([]string, 0), values: map[string]interface{}{}}
+		for decoder.More() {
+			key, err := decoder.Token()
+			if err != nil {
+				return nil, err
+			}
+			value, err := decodeJSONValue(decoder)
+			if err != nil {
+				return nil, err
+			}
+			name := key.(string)
+			if _, ok := o.values[name]; !ok {
+				o.keys = append(o.keys, name)
+			}
+			o.values[name] = value
+		}
+		_, err := decoder.Token()
+		return o, err
+	case json.Delim('['):
+		items := make([]interface{}, 0)
+		for decoder.More() {
+			value, err := decodeJSONValue(decoder)
+			if err != nil {
+				return nil, err
+			}
+			items = append(items, value)
+		}
+		_, err := decoder.Token()
+		return items, err
+	}
+	return token, nil
+}
+
+func jsonNumber(n json.Number) interface{} {
+	if i, err := n.Int64(); err == nil {
+		return i
+	}
+	f, _ := n.Float64()
+	return f
+}
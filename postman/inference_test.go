@@ -0,0 +1,86 @@
+package postman
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestInferStructures(t *testing.T) {
+	// Given
+	builder := &CollectionBuilder{}
+	col := Collection{
+		Structures: []StructureDefinition{{Name: "Delete a cat (204 response)"}},
+		Folders: []Folder{{Name: "Cats", Requests: []Request{
+			{
+				Name:       "Create a cat",
+				PayloadRaw: `{"name": "Felix", "age": 3}`,
+				Responses: []Response{
+					{StatusCode: 201, Body: `{"id": 1, "name": "Felix", "age": 3, "owner": null, "tags": ["black"]}`,
+						OriginalRequest: &Request{PayloadRaw: `{"name": "Garfield", "age": 5.5, "color": "orange"}`}},
+					{StatusCode: 201, Body: `{"id": 2, "name": "Garfield", "age": 5.5, "owner": {"name": "Jon"}, "tags": []}`},
+					{StatusCode: 400, Body: `{"error": "invalid name"}`},
+					{StatusCode: 500, Body: `Internal server error`},
+				},
+			},
+			{
+				Name:      "Delete a cat",
+				Responses: []Response{{StatusCode: 204, Body: `{"deleted": true}`}},
+			},
+		}}},
+	}
+
+	// When
+	builder.inferStructures(&col)
+
+	// Then
+	expected := []StructureDefinition{
+		{Name: "Delete a cat (204 response)"},
+		{Name: "Create a cat (request)", Description: "Inferred from 2 example(s).", Inferred: true, Fields: []StructureFieldDefinition{
+			{Name: "name", Type: "string", Required: true, Example: "Felix"},
+			{Name: "age", Type: "number", Required: true, Example: int64(3)},
+			{Name: "color", Type: "string", Example: "orange"},
+		}},
+		{Name: "Create a cat (201 response)", Description: "Inferred from 2 example(s).", Inferred: true, Fields: []StructureFieldDefinition{
+			{Name: "id", Type: "integer", Required: true, Example: int64(1)},
+			{Name: "name", Type: "string", Required: true, Example: "Felix"},
+			{Name: "age", Type: "number", Required: true, Example: int64(3)},
+			{Name: "owner", Type: "object", Required: true, Nullable: true, Fields: []StructureFieldDefinition{
+				{Name: "name", Type: "string", Required: true, Example: "Jon"},
+			}},
+			{Name: "tags", Type: "array", Required: true, Items: &StructureFieldDefinition{Type: "string", Example: "black"}},
+		}},
+		{Name: "Create a cat (400 response)", Description: "Inferred from 1 example(s).", Inferred: true, Fields: []StructureFieldDefinition{
+			{Name: "error", Type: "string", Required: true, Example: "invalid name"},
+		}},
+	}
+	if !reflect.DeepEqual(col.Structures, expected) {
+		t.Errorf("Structures were not properly inferred, expected %+v, got %+v", expected, col.Structures)
+	}
+}
+
+func TestInferStructureFromArrays(t *testing.T) {
+	// When
+	structure, ok := inferStructure("List cats (200 response)", []string{`[{"id": 1, "name": "Felix"}, {"id": 2}]`, `[]`})
+
+	// Then
+	if !ok {
+		t.Fatal("Expected a structure to be inferred")
+	}
+	expectedFields := []StructureFieldDefinition{
+		{Name: "id", Type: "integer", Required: true, Example: int64(1)},
+		{Name: "name", Type: "string", Example: "Felix"},
+	}
+	if !reflect.DeepEqual(structure.Fields, expectedFields) {
+		t.Errorf("Expected fields %+v, got %+v", expectedFields, structure.Fields)
+	}
+}
+
+func TestInferStructureWithoutJSON(t *testing.T) {
+	// When
+	_, ok := inferStructure("Get the logo (200 response)", []string{"", "<svg></svg>", `"a string"`, `{"a": 1} trailing`})
+
+	// Then
+	if ok {
+		t.Error("No structure should be inferred from bodies that are not JSON objects")
+	}
+}
@@ -0,0 +1,83 @@
+package postman
+
+import (
+	"fmt"
+	"time"
+)
+
+const sunsetDateLayout = "2006-01-02"
+
+// Lifecycle tells when an endpoint appeared, and whether it is deprecated and when it will be removed.
+type Lifecycle struct {
+	Deprecated bool
+	// Sunset is the date the endpoint is removed on, if known.
+	Sunset *time.Time
+	// ReplacedBy is the name of the request that replaces the endpoint.
+	ReplacedBy string
+	Since      string
+}
+
+// PastSunset tells whether the endpoint is removed at the given time.
+func (l Lifecycle) PastSunset(now time.Time) bool {
+	return l.Sunset != nil && !now.Before(*l.Sunset)
+}
+
+// FindRequestByName returns the first request with the given name, searching the folders as well, or nil if there is none.
+func (c Collection) FindRequestByName(name string) *Request {
+	return Folder{Requests: c.Requests, Folders: c.Folders}.findRequestByName(name)
+}
+
+func (f Folder) findRequestByName(name string) *Request {
+	for _, request := range f.Requests {
+		if request.Name == name {
+			return &request
+		}
+	}
+	for _, folder := range f.Folders {
+		if request := folder.findRequestByName(name); request != nil {
+			return request
+		}
+	}
+	return nil
+}
+
+// extractLifecycles sets the lifecycle of each request from the "deprecated", "sunset", "replaced-by"
+// and "since" entries of its metadata. A sunset date or a replacement implies the request is deprecated,
+// and so does a "deprecated" entry that is not false, such as "@deprecated use the v2 endpoint".
+func (c *CollectionBuilder) extractLifecycles(col *Collection) {
+	walkRequests(col, func(_ []*Folder, request *Request) {
+		request.Lifecycle = c.buildLifecycle(col, *request)
+	})
+}
+
+func (c *CollectionBuilder) buildLifecycle(col *Collection, request Request) Lifecycle {
+	deprecated, ok := request.Meta["deprecated"]
+	lifecycle := Lifecycle{
+		Deprecated: ok && deprecated != false && deprecated != "false",
+		ReplacedBy: request.Meta.String("replaced-by"),
+		Since:      request.Meta.String("since"),
+	}
+
+	if sunset := request.Meta.String("sunset"); sunset != "" {
+		date, err := parseSunsetDate(sunset)
+		if err != nil {
+			col.Warnings = append(col.Warnings, fmt.Sprintf("%v: invalid sunset date %v, expected a date such as 2006-01-02", request.Name, sunset))
+		} else {
+			lifecycle.Sunset = &date
+		}
+	}
+
+	if lifecycle.ReplacedBy != "" && col.FindRequestByName(lifecycle.ReplacedBy) == nil {
+		col.Warnings = append(col.Warnings, fmt.Sprintf("%v: replacement request %v not found", request.Name, lifecycle.ReplacedBy))
+	}
+
+	lifecycle.Deprecated = lifecycle.Deprecated || lifecycle.Sunset != nil || lifecycle.ReplacedBy != ""
+	return lifecycle
+}
+
+func parseSunsetDate(value string) (time.Time, error) {
+	if date, err := time.Parse(time.RFC3339, value); err == nil {
+		return date, nil
+	}
+	return time.Parse(sunsetDateLayout, value)
+}
@@ -0,0 +1,76 @@
+package postman
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestExtractLifecycles(t *testing.T) {
+	// Given
+	builder := &CollectionBuilder{}
+	col := Collection{
+		Requests: []Request{
+			{Name: "Get all cats", Description: "---\nsunset: 2024-12-31\nreplaced-by: Get all cats v2\n---\nAll the cats."},
+			{Name: "Get all cats v2", Description: "@since v2.0\n\nAll the cats, paginated."},
+		},
+		Folders: []Folder{{Name: "Dogs", Requests: []Request{
+			{Name: "Get all dogs", Description: "@deprecated use the cats instead"},
+			{Name: "Get one dog", Description: "@sunset tomorrow\n@replaced-by Get one wolf"},
+			{Name: "Delete a dog", Description: "@deprecated false"},
+		}}},
+	}
+
+	// When
+	builder.extractMeta(&col)
+	builder.extractLifecycles(&col)
+
+	// Then
+	sunset := time.Date(2024, 12, 31, 0, 0, 0, 0, time.UTC)
+	expected := map[string]Lifecycle{
+		"Get all cats":    {Deprecated: true, Sunset: &sunset, ReplacedBy: "Get all cats v2"},
+		"Get all cats v2": {Since: "v2.0"},
+		"Get all dogs":    {Deprecated: true},
+		"Get one dog":     {Deprecated: true, ReplacedBy: "Get one wolf"},
+		"Delete a dog":    {},
+	}
+	requests := append(append([]Request{}, col.Requests...), col.Folders[0].Requests...)
+	for _, request := range requests {
+		if !reflect.DeepEqual(request.Lifecycle, expected[request.Name]) {
+			t.Errorf("Expected the lifecycle of %v to be %+v, got %+v", request.Name, expected[request.Name], request.Lifecycle)
+		}
+	}
+	expectedWarnings := []string{
+		"Get one dog: invalid sunset date tomorrow, expected a date such as 2006-01-02",
+		"Get one dog: replacement request Get one wolf not found",
+	}
+	if !reflect.DeepEqual(col.Warnings, expectedWarnings) {
+		t.Errorf("Expected warnings %q, got %q", expectedWarnings, col.Warnings)
+	}
+}
+
+func TestLifecyclePastSunset(t *testing.T) {
+	sunset := time.Date(2024, 12, 31, 0, 0, 0, 0, time.UTC)
+	lifecycle := Lifecycle{Deprecated: true, Sunset: &sunset}
+
+	if lifecycle.PastSunset(sunset.Add(-time.Second)) {
+		t.Error("The endpoint should not be past its sunset before the sunset date")
+	}
+	if !lifecycle.PastSunset(sunset) {
+		t.Error("The endpoint should be past its sunset on the sunset date")
+	}
+	if (Lifecycle{Deprecated: true}).PastSunset(sunset) {
+		t.Error("An endpoint without sunset date should never be past its sunset")
+	}
+}
+
+func TestFindRequestByName(t *testing.T) {
+	col := Collection{Folders: []Folder{{Folders: []Folder{{Requests: []Request{{ID: "1", Name: "Get one dog"}}}}}}}
+
+	if request := col.FindRequestByName("Get one dog"); request == nil || request.ID != "1" {
+		t.Errorf("Expected to find the request in the nested folders, got %v", request)
+	}
+	if request := col.FindRequestByName("Get one wolf"); request != nil {
+		t.Errorf("Expected no request, got %v", request)
+	}
+}
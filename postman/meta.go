@@ -0,0 +1,153 @@
+package postman
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	yaml "gopkg.in/yaml.v2"
+)
+
+var (
+	frontMatterPattern = regexp.MustCompile(`(?s)^---[ \t]*\r?\n(.*?)\r?\n---[ \t]*(?:\r?\n|$)`)
+	metaLinePattern    = regexp.MustCompile(`^@([\w.-]+)(?:[ \t]+(.*?))?[ \t]*\r?$`)
+	yamlKeyPattern     = regexp.MustCompile(`\A\s*[\w.-]+:(?:\s|\z)`)
+)
+
+// Meta holds the metadata written at the top of a description, such as tags or the version an endpoint appeared in.
+type Meta map[string]interface{}
+
+// Tags returns the tags of the metadata.
+func (m Meta) Tags() []string {
+	return m.List("tags")
+}
+
+// List returns an entry of the metadata given as a list or as a comma separated string.
+func (m Meta) List(key string) []string {
+	values := make([]string, 0)
+	switch v := m[key].(type) {
+	case []interface{}:
+		for _, value := range v {
+			values = append(values, fmt.Sprint(value))
+		}
+	case string:
+		for _, value := range strings.Split(v, ",") {
+			if value = strings.TrimSpace(value); value != "" {
+				values = append(values, value)
+			}
+		}
+	}
+	return values
+}
+
+// HasTag tells whether the metadata has the given tag, a boolean entry such as "internal: true" counting as a tag.
+func (m Meta) HasTag(tag string) bool {
+	if m[tag] == true {
+		return true
+	}
+	for _, t := range m.Tags() {
+		if t == tag {
+			return true
+		}
+	}
+	return false
+}
+
+// String returns an entry of the metadata as a string, or an empty string.
+func (m Meta) String(key string) string {
+	if value, ok := m[key]; ok && value != nil {
+		return fmt.Sprint(value)
+	}
+	return ""
+}
+
+// extractMeta returns a description without its front matter, and the metadata it contains. The front matter is
+// either a YAML block between "---" lines, or "@key value" lines. A "@key" line without value sets a boolean entry,
+// and "@tag" lines add up to the tags. A block between "---" lines that is not a YAML mapping is prose between
+// horizontal rules, and is left in the description.
+func extractMeta(description string) (string, Meta, error) {
+	if match := frontMatterPattern.FindStringSubmatch(description); match != nil {
+		var mapping yaml.MapSlice
+		if err := yaml.Unmarshal([]byte(match[1]), &mapping); err != nil {
+			if !yamlKeyPattern.MatchString(match[1]) {
+				return description, nil, nil
+			}
+			return description, nil, err
+		}
+		meta := Meta{}
+		for key, value := range plain(normalize(mapping)).(map[string]interface{}) {
+			meta[key] = value
+		}
+		return strings.TrimSpace(description[len(match[0]):]), meta, nil
+	}
+
+	var meta Meta
+	lines := strings.Split(description, "\n")
+	kept := make([]string, 0, len(lines))
+	i := 0
+	for ; i < len(lines); i++ {
+		match := metaLinePattern.FindStringSubmatch(lines[i])
+		if match == nil {
+			break
+		}
+		if match[1] == "body" || match[1] == "returns" {
+			kept = append(kept, lines[i])
+			continue
+		}
+		if meta == nil {
+			meta = Meta{}
+		}
+		meta.set(match[1], match[2])
+	}
+	if meta == nil {
+		return description, nil, nil
+	}
+	kept = append(kept, lines[i:]...)
+	return strings.TrimSpace(strings.Join(kept, "\n")), meta, nil
+}
+
+func (m Meta) set(key, value string) {
+	switch {
+	case key == "tag" || key == "tags":
+		tags, _ := m["tags"].([]interface{})
+		for _, tag := range strings.Split(value, ",") {
+			if tag = strings.TrimSpace(tag); tag != "" {
+				tags = append(tags, tag)
+			}
+		}
+		m["tags"] = tags
+	case value == "":
+		m[key] = true
+	default:
+		m[key] = value
+	}
+}
+
+// extractMeta sets the metadata of the collection, of the folders and of the requests.
+func (c *CollectionBuilder) extractMeta(col *Collection) {
+	col.Description, col.Meta = c.extractDescriptionMeta(col, col.Name, col.Description)
+	root := Folder{Requests: col.Requests, Folders: col.Folders}
+	c.extractFolderMeta(col, &root)
+	col.Requests = root.Requests
+	col.Folders = root.Folders
+}
+
+func (c *CollectionBuilder) extractFolderMeta(col *Collection, folder *Folder) {
+	for i := range folder.Requests {
+		request := &folder.Requests[i]
+		request.Description, request.Meta = c.extractDescriptionMeta(col, request.Name, request.Description)
+	}
+	for i := range folder.Folders {
+		f := &folder.Folders[i]
+		f.Description, f.Meta = c.extractDescriptionMeta(col, f.Name, f.Description)
+		c.extractFolderMeta(col, f)
+	}
+}
+
+func (c *CollectionBuilder) extractDescriptionMeta(col *Collection, owner, description string) (string, Meta) {
+	description, meta, err := extractMeta(description)
+	if err != nil {
+		col.Warnings = append(col.Warnings, fmt.Sprintf("%v description: invalid front matter: %v", owner, err))
+	}
+	return description, meta
+}
@@ -0,0 +1,109 @@
+package postman
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestExtractMeta(t *testing.T) {
+	testCases := []struct {
+		name                string
+		description         string
+		expectedDescription string
+		expectedMeta        Meta
+	}{
+		{
+			name:                "YAML front matter",
+			description:         "---\ntags: [public, beta]\nsince: v2.3\ninternal: false\n---\nGet all the cats.",
+			expectedDescription: "Get all the cats.",
+			expectedMeta:        Meta{"tags": []interface{}{"public", "beta"}, "since": "v2.3", "internal": false},
+		},
+		{
+			name:                "annotations",
+			description:         "@tag public\n@tags beta, partner\n@since v2.3\n@internal\n@returns Cat\n\nGet all the cats.\n@deprecated is not metadata here",
+			expectedDescription: "@returns Cat\n\nGet all the cats.\n@deprecated is not metadata here",
+			expectedMeta:        Meta{"tags": []interface{}{"public", "beta", "partner"}, "since": "v2.3", "internal": true},
+		},
+		{
+			name:                "horizontal rules",
+			description:         "---\nCats are good for health, use this endpoint wisely.\n---\nGet all the cats.",
+			expectedDescription: "---\nCats are good for health, use this endpoint wisely.\n---\nGet all the cats.",
+		},
+		{
+			name:                "horizontal rules around a list",
+			description:         "---\n- cats\n- dogs\n---\nGet all the pets.",
+			expectedDescription: "---\n- cats\n- dogs\n---\nGet all the pets.",
+		},
+		{
+			name:                "no metadata",
+			description:         "Get all the cats.\n---\nNot a front matter\n---",
+			expectedDescription: "Get all the cats.\n---\nNot a front matter\n---",
+		},
+	}
+
+	for _, tc := range testCases {
+		// When
+		description, meta, err := extractMeta(tc.description)
+
+		// Then
+		if err != nil {
+			t.Errorf("%v: unexpected error: %v", tc.name, err)
+		}
+		if description != tc.expectedDescription {
+			t.Errorf("%v: expected description %q, got %q", tc.name, tc.expectedDescription, description)
+		}
+		if !reflect.DeepEqual(meta, tc.expectedMeta) {
+			t.Errorf("%v: expected meta %v, got %v", tc.name, tc.expectedMeta, meta)
+		}
+	}
+}
+
+func TestExtractMetaInvalidFrontMatter(t *testing.T) {
+	// Given
+	builder := &CollectionBuilder{}
+	col := Collection{Folders: []Folder{{Name: "Cats", Description: "---\ntags: [public\n---\nAll the cats."}}}
+
+	// When
+	builder.extractMeta(&col)
+
+	// Then
+	if col.Folders[0].Description != "---\ntags: [public\n---\nAll the cats." || col.Folders[0].Meta != nil {
+		t.Errorf("An invalid front matter should be left in the description, got %q and %v", col.Folders[0].Description, col.Folders[0].Meta)
+	}
+	if len(col.Warnings) != 1 {
+		t.Errorf("Expected a warning about the invalid front matter, got %q", col.Warnings)
+	}
+}
+
+func TestExtractMetaHorizontalRules(t *testing.T) {
+	// Given
+	builder := &CollectionBuilder{}
+	col := Collection{Requests: []Request{{Name: "Get all cats", Description: "---\nCats are good for *health*!\n---"}}}
+
+	// When
+	builder.extractMeta(&col)
+
+	// Then
+	if col.Requests[0].Description != "---\nCats are good for *health*!\n---" || col.Requests[0].Meta != nil {
+		t.Errorf("Prose between horizontal rules should be left in the description, got %q and %v", col.Requests[0].Description, col.Requests[0].Meta)
+	}
+	if len(col.Warnings) != 0 {
+		t.Errorf("Expected no warning, got %q", col.Warnings)
+	}
+}
+
+func TestMetaTags(t *testing.T) {
+	meta := Meta{"tags": "public, beta", "internal": true, "beta": "no", "since": 2.3}
+
+	if tags := meta.Tags(); !reflect.DeepEqual(tags, []string{"public", "beta"}) {
+		t.Errorf("Expected the tags public and beta, got %q", tags)
+	}
+	for tag, expected := range map[string]bool{"public": true, "internal": true, "beta": true, "since": false, "partner": false} {
+		if meta.HasTag(tag) != expected {
+			t.Errorf("Expected HasTag(%q) to be %v", tag, expected)
+		}
+	}
+	if since := meta.String("since"); since != "2.3" {
+		t.Errorf("Expected since to be 2.3, got %q", since)
+	}
+}
@@ -0,0 +1,200 @@
+package postman
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// RedactedValue replaces the secrets found in a collection.
+const RedactedValue = "[REDACTED]"
+
+const sensitiveKeys = `token|secret|password|passwd|api[-_]?key|session`
+
+var (
+	sensitiveHeaders    = map[string]bool{"authorization": true, "proxy-authorization": true, "cookie": true, "set-cookie": true}
+	sensitiveKeyPattern = regexp.MustCompile(`(?i)` + sensitiveKeys)
+	sensitiveJSONValue  = regexp.MustCompile(`("[^"]*(?i:` + sensitiveKeys + `)[^"]*"\s*:\s*")((?:[^"\\]|\\.)*)"`)
+	// sensitiveScriptValue matches the string literals following a sensitive key in a script, as in an object
+	// literal or in a call such as pm.environment.set("token", "value").
+	sensitiveScriptValue = regexp.MustCompile(`(["'][^"']*(?i:` + sensitiveKeys + `)[^"']*["']\s*[:,]\s*)(?:"((?:[^"\\]|\\.)*)"|'((?:[^'\\]|\\.)*)')`)
+	sensitiveQueryValue  = regexp.MustCompile(`[?&][^=&#]*(?i:` + sensitiveKeys + `)[^=&#]*=([^&#]*)`)
+	jwtPattern           = regexp.MustCompile(`\beyJ[A-Za-z0-9_-]+\.[A-Za-z0-9_-]+\.[A-Za-z0-9_-]*`)
+	variablesPattern     = regexp.MustCompile(`^\s*(?:\w+\s+)?(?:\{\{[^{}]+\}\}\s*)+$`)
+	authSchemePattern    = regexp.MustCompile(`^(\w+\s+)\S`)
+)
+
+// Redaction tells where a secret was redacted, and which rule found it.
+type Redaction struct {
+	Location string
+	Rule     string
+}
+
+type redactor struct {
+	patterns   []*regexp.Regexp
+	redactions []Redaction
+}
+
+func newRedactor(patterns []string) (*redactor, error) {
+	r := &redactor{}
+	for _, pattern := range patterns {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid redaction pattern %v: %v", pattern, err)
+		}
+		r.patterns = append(r.patterns, re)
+	}
+	return r, nil
+}
+
+// redactCollection replaces the secrets of the requests, of their responses and of the scripts, and reports them in
+// col.Redactions.
+func (r *redactor) redactCollection(col *Collection) {
+	col.PreRequestScript = r.redactScript("collection pre-request script", col.PreRequestScript)
+	col.Tests = r.redactScript("collection tests", col.Tests)
+	r.redactFolderScripts("", col.Folders)
+	walkRequests(col, func(path []*Folder, request *Request) {
+		location := ""
+		for _, folder := range path {
+			location = joinPath(location, folder.Name)
+		}
+		location = joinPath(location, request.Name)
+
+		r.redactRequest(request, location+", request")
+		for i := range request.Responses {
+			response := &request.Responses[i]
+			responseLocation := fmt.Sprintf("%v, response %q", location, response.Name)
+			response.Body = r.redactBody(responseLocation+" body", response.Body)
+			r.redactHeaders(responseLocation+" header", response.Headers)
+			if response.OriginalRequest != nil {
+				r.redactRequest(response.OriginalRequest, responseLocation+" original request")
+			}
+		}
+	})
+	col.Redactions = append(col.Redactions, r.redactions...)
+}
+
+func (r *redactor) redactRequest(request *Request, location string) {
+	request.URL = r.redactURL(location+" URL", request.URL)
+	r.redactHeaders(location+" header", request.Headers)
+	request.PayloadRaw = r.redactBody(location+" body", request.PayloadRaw)
+	r.redactParams(location+" parameter", request.PayloadParams)
+	r.redactParams(location+" path variable", request.PathVariables)
+	request.PreRequestScript = r.redactScript(location+" pre-request script", request.PreRequestScript)
+	request.Tests = r.redactScript(location+" tests", request.Tests)
+}
+
+func (r *redactor) redactFolderScripts(location string, folders []Folder) {
+	for i := range folders {
+		folderLocation := joinPath(location, folders[i].Name)
+		folders[i].PreRequestScript = r.redactScript(folderLocation+", pre-request script", folders[i].PreRequestScript)
+		folders[i].Tests = r.redactScript(folderLocation+", tests", folders[i].Tests)
+		r.redactFolderScripts(folderLocation, folders[i].Folders)
+	}
+}
+
+// redactHeaders replaces the values of the sensitive headers, keeping the scheme of the authorization headers.
+func (r *redactor) redactHeaders(location string, headers []KeyValuePair) {
+	for i := range headers {
+		value, ok := headers[i].Value.(string)
+		if !ok {
+			continue
+		}
+		name := strings.ToLower(headers[i].Key)
+		headerLocation := location + " " + headers[i].Key
+		if (sensitiveHeaders[name] || sensitiveKeyPattern.MatchString(name)) && !isPlaceholder(value) {
+			redacted := RedactedValue
+			if strings.HasSuffix(name, "authorization") {
+				if match := authSchemePattern.FindStringSubmatch(value); match != nil {
+					redacted = match[1] + RedactedValue
+				}
+			}
+			headers[i].Value = redacted
+			r.record(headerLocation, "sensitive header")
+			continue
+		}
+		headers[i].Value = r.redactString(headerLocation, value)
+	}
+}
+
+func (r *redactor) redactParams(location string, params []KeyValuePair) {
+	for i := range params {
+		value, ok := params[i].Value.(string)
+		if !ok {
+			continue
+		}
+		paramLocation := location + " " + params[i].Key
+		if sensitiveKeyPattern.MatchString(params[i].Key) && value != "" && !isPlaceholder(value) {
+			params[i].Value = RedactedValue
+			r.record(paramLocation, "sensitive key")
+			continue
+		}
+		params[i].Value = r.redactString(paramLocation, value)
+	}
+}
+
+func (r *redactor) redactURL(location, url string) string {
+	url = r.redactGroup(location, "sensitive key", sensitiveQueryValue, 1, url)
+	return r.redactString(location, url)
+}
+
+func (r *redactor) redactBody(location, body string) string {
+	body = r.redactGroup(location, "sensitive key", sensitiveJSONValue, 2, body)
+	return r.redactString(location, body)
+}
+
+// redactScript redacts the string literals of the sensitive keys of a script, which are single or double quoted.
+func (r *redactor) redactScript(location, script string) string {
+	script = r.redactGroup(location, "sensitive key", sensitiveScriptValue, 2, script)
+	script = r.redactGroup(location, "sensitive key", sensitiveScriptValue, 3, script)
+	return r.redactString(location, script)
+}
+
+// redactString applies the rules that do not depend on where the value is: JWTs, and the user defined patterns.
+// When a user defined pattern has a group, only the first group is replaced.
+func (r *redactor) redactString(location, value string) string {
+	value = r.redactGroup(location, "JWT", jwtPattern, 0, value)
+	for _, pattern := range r.patterns {
+		group := 0
+		if pattern.NumSubexp() > 0 {
+			group = 1
+		}
+		value = r.redactGroup(location, fmt.Sprintf("pattern %v", pattern), pattern, group, value)
+	}
+	return value
+}
+
+func (r *redactor) redactGroup(location, rule string, pattern *regexp.Regexp, group int, value string) string {
+	var redacted strings.Builder
+	last := 0
+	for _, match := range pattern.FindAllStringSubmatchIndex(value, -1) {
+		start, end := match[2*group], match[2*group+1]
+		if start < 0 || start == end || isPlaceholder(value[start:end]) {
+			continue
+		}
+		redacted.WriteString(value[last:start])
+		redacted.WriteString(RedactedValue)
+		last = end
+		r.record(location, rule)
+	}
+	if last == 0 {
+		return value
+	}
+	redacted.WriteString(value[last:])
+	return redacted.String()
+}
+
+func (r *redactor) record(location, rule string) {
+	redaction := Redaction{Location: location, Rule: rule}
+	for _, existing := range r.redactions {
+		if existing == redaction {
+			return
+		}
+	}
+	r.redactions = append(r.redactions, redaction)
+}
+
+// isPlaceholder tells whether a value is made of Postman variables, such as "Bearer {{token}}", or is already redacted.
+func isPlaceholder(value string) bool {
+	return variablesPattern.MatchString(value) || strings.Contains(value, RedactedValue)
+}
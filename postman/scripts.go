@@ -0,0 +1,55 @@
+package postman
+
+// Script is a piece of Javascript attached to the collection, to a folder or to a request.
+type Script struct {
+	// Owner is the name of the collection, folder or request the script is attached to.
+	Owner  string
+	Source string
+}
+
+// ScriptChain holds the scripts run around a request, in the order Postman runs them:
+// the collection scripts first, then the scripts of each enclosing folder, then the request ones.
+type ScriptChain struct {
+	PreRequest []Script
+	Tests      []Script
+}
+
+// ScriptChain returns the effective scripts of the request with the given ID.
+func (c Collection) ScriptChain(requestID string) (ScriptChain, error) {
+	root := Folder{Name: c.Name, PreRequestScript: c.PreRequestScript, Tests: c.Tests, Requests: c.Requests, Folders: c.Folders}
+	chain := ScriptChain{PreRequest: make([]Script, 0), Tests: make([]Script, 0)}
+	if !root.buildScriptChain(requestID, &chain) {
+		return chain, ErrRequestNotFound
+	}
+	return chain, nil
+}
+
+func (f Folder) buildScriptChain(requestID string, chain *ScriptChain) bool {
+	for _, request := range f.Requests {
+		if request.ID == requestID {
+			chain.prepend(f.Name, f.PreRequestScript, f.Tests)
+			chain.PreRequest = appendScript(chain.PreRequest, request.Name, request.PreRequestScript)
+			chain.Tests = appendScript(chain.Tests, request.Name, request.Tests)
+			return true
+		}
+	}
+	for _, folder := range f.Folders {
+		if folder.buildScriptChain(requestID, chain) {
+			chain.prepend(f.Name, f.PreRequestScript, f.Tests)
+			return true
+		}
+	}
+	return false
+}
+
+func (chain *ScriptChain) prepend(owner, preRequestScript, tests string) {
+	chain.PreRequest = append(appendScript(make([]Script, 0), owner, preRequestScript), chain.PreRequest...)
+	chain.Tests = append(appendScript(make([]Script, 0), owner, tests), chain.Tests...)
+}
+
+func appendScript(scripts []Script, owner, source string) []Script {
+	if source == "" {
+		return scripts
+	}
+	return append(scripts, Script{Owner: owner, Source: source})
+}
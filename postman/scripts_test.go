@@ -0,0 +1,69 @@
+package postman
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestScriptChain(t *testing.T) {
+	// Given
+	col := Collection{
+		Name:             "Cats API",
+		PreRequestScript: "collection pre-request",
+		Tests:            "collection tests",
+		Requests:         []Request{{ID: "1", Name: "Ping"}},
+		Folders: []Folder{{
+			Name:  "Cats",
+			Tests: "cats tests",
+			Folders: []Folder{{
+				Name:             "Kittens",
+				PreRequestScript: "kittens pre-request",
+				Requests: []Request{{
+					ID:               "2",
+					Name:             "Get all kittens",
+					PreRequestScript: "request pre-request",
+					Tests:            "request tests",
+				}},
+			}},
+		}},
+	}
+
+	// When
+	chain, err := col.ScriptChain("2")
+
+	// Then
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	expectedChain := ScriptChain{
+		PreRequest: []Script{
+			{Owner: "Cats API", Source: "collection pre-request"},
+			{Owner: "Kittens", Source: "kittens pre-request"},
+			{Owner: "Get all kittens", Source: "request pre-request"},
+		},
+		Tests: []Script{
+			{Owner: "Cats API", Source: "collection tests"},
+			{Owner: "Cats", Source: "cats tests"},
+			{Owner: "Get all kittens", Source: "request tests"},
+		},
+	}
+	if !reflect.DeepEqual(chain, expectedChain) {
+		t.Errorf("Script chain was not properly built, expected %+v, got %+v", expectedChain, chain)
+	}
+
+	// When
+	chain, err = col.ScriptChain("1")
+
+	// Then
+	if err != nil || len(chain.Tests) != 1 || chain.Tests[0].Source != "collection tests" {
+		t.Errorf("Script chain of a root request was not properly built, got %+v, %v", chain, err)
+	}
+
+	// When
+	_, err = col.ScriptChain("unknown")
+
+	// Then
+	if err != ErrRequestNotFound {
+		t.Errorf("Expected ErrRequestNotFound, got %v", err)
+	}
+}
@@ -0,0 +1,120 @@
+package postman
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Sort orders supported by BuilderOptions.Sort.
+const (
+	SortNone   = "none"
+	SortAlpha  = "alpha"
+	SortMethod = "method"
+	SortPath   = "path"
+)
+
+var methodRanks = map[string]int{"GET": 0, "POST": 1, "PUT": 2, "PATCH": 3, "DELETE": 4, "HEAD": 5, "OPTIONS": 6}
+
+// requestLess reports whether a request comes before another one in a sort order.
+type requestLess func(a, b Request) bool
+
+func newRequestLess(order string) (requestLess, error) {
+	switch order {
+	case "", SortNone:
+		return nil, nil
+	case SortAlpha:
+		return func(a, b Request) bool {
+			return strings.ToLower(a.Name) < strings.ToLower(b.Name)
+		}, nil
+	case SortMethod:
+		return func(a, b Request) bool {
+			if methodRank(a.Method) != methodRank(b.Method) {
+				return methodRank(a.Method) < methodRank(b.Method)
+			}
+			return a.Path() < b.Path()
+		}, nil
+	case SortPath:
+		return func(a, b Request) bool {
+			if a.Path() != b.Path() {
+				return a.Path() < b.Path()
+			}
+			return methodRank(a.Method) < methodRank(b.Method)
+		}, nil
+	}
+	return nil, fmt.Errorf("unknown sort order %v, expected %v, %v, %v or %v", order, SortAlpha, SortMethod, SortPath, SortNone)
+}
+
+func methodRank(method string) int {
+	if rank, ok := methodRanks[strings.ToUpper(method)]; ok {
+		return rank
+	}
+	return len(methodRanks)
+}
+
+// sortCollection sorts the requests and the folders of the collection, folders being sorted by name unless the order
+// is "none". The items listed in the "order" entry of the metadata of a folder, or of the collection, come first.
+func (c *CollectionBuilder) sortCollection(col *Collection, order string) error {
+	less, err := newRequestLess(order)
+	if err != nil {
+		return err
+	}
+	root := Folder{Name: col.Name, Meta: col.Meta, Requests: col.Requests, Folders: col.Folders}
+	c.sortFolder(col, &root, less)
+	col.Requests = root.Requests
+	col.Folders = root.Folders
+	return nil
+}
+
+func (c *CollectionBuilder) sortFolder(col *Collection, folder *Folder, less requestLess) {
+	if less != nil {
+		sort.SliceStable(folder.Requests, func(i, j int) bool {
+			return less(folder.Requests[i], folder.Requests[j])
+		})
+		sort.SliceStable(folder.Folders, func(i, j int) bool {
+			return strings.ToLower(folder.Folders[i].Name) < strings.ToLower(folder.Folders[j].Name)
+		})
+	}
+
+	if pinned := folder.Meta.List("order"); len(pinned) > 0 {
+		ranks := map[string]int{}
+		for i, name := range pinned {
+			ranks[name] = i + 1
+		}
+		found := map[string]bool{}
+		sort.SliceStable(folder.Requests, func(i, j int) bool {
+			return pinnedLess(ranks, folder.Requests[i].Name, folder.Requests[j].Name)
+		})
+		sort.SliceStable(folder.Folders, func(i, j int) bool {
+			return pinnedLess(ranks, folder.Folders[i].Name, folder.Folders[j].Name)
+		})
+		for _, request := range folder.Requests {
+			found[request.Name] = true
+		}
+		for _, f := range folder.Folders {
+			found[f.Name] = true
+		}
+		for _, name := range pinned {
+			if !found[name] {
+				col.Warnings = append(col.Warnings, fmt.Sprintf("%v: order refers to unknown item %v", folder.Name, name))
+			}
+		}
+	}
+
+	for i := range folder.Folders {
+		c.sortFolder(col, &folder.Folders[i], less)
+	}
+}
+
+// pinnedLess puts the pinned items first, in the given order.
+func pinnedLess(ranks map[string]int, a, b string) bool {
+	rankA, pinnedA := ranks[a]
+	rankB, pinnedB := ranks[b]
+	switch {
+	case pinnedA && pinnedB:
+		return rankA < rankB
+	case pinnedA != pinnedB:
+		return pinnedA
+	}
+	return false
+}
@@ -0,0 +1,107 @@
+package postman
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSortCollection(t *testing.T) {
+	newCollection := func() Collection {
+		return Collection{
+			Name: "Cats API",
+			Requests: []Request{
+				{Name: "delete a cat", Method: "DELETE", URL: "{{url}}/cats/:id"},
+				{Name: "Get all cats", Method: "GET", URL: "{{url}}/cats"},
+				{Name: "Create a cat", Method: "POST", URL: "http://localhost:8080/cats"},
+				{Name: "Get one cat", Method: "GET", URL: "{{url}}/cats/:id"},
+			},
+			Folders: []Folder{{Name: "dogs"}, {Name: "Birds"}},
+		}
+	}
+
+	testCases := []struct {
+		order           string
+		expected        []string
+		expectedFolders []string
+	}{
+		{order: "", expected: []string{"delete a cat", "Get all cats", "Create a cat", "Get one cat"}, expectedFolders: []string{"dogs", "Birds"}},
+		{order: SortNone, expected: []string{"delete a cat", "Get all cats", "Create a cat", "Get one cat"}, expectedFolders: []string{"dogs", "Birds"}},
+		{order: SortAlpha, expected: []string{"Create a cat", "delete a cat", "Get all cats", "Get one cat"}, expectedFolders: []string{"Birds", "dogs"}},
+		{order: SortMethod, expected: []string{"Get all cats", "Get one cat", "Create a cat", "delete a cat"}, expectedFolders: []string{"Birds", "dogs"}},
+		{order: SortPath, expected: []string{"Get all cats", "Create a cat", "Get one cat", "delete a cat"}, expectedFolders: []string{"Birds", "dogs"}},
+	}
+
+	for _, tc := range testCases {
+		// Given
+		builder := &CollectionBuilder{}
+		col := newCollection()
+
+		// When
+		err := builder.sortCollection(&col, tc.order)
+
+		// Then
+		if err != nil {
+			t.Errorf("Unexpected error for order %q: %v", tc.order, err)
+		}
+		names := make([]string, 0)
+		for _, request := range col.Requests {
+			names = append(names, request.Name)
+		}
+		folderNames := make([]string, 0)
+		for _, folder := range col.Folders {
+			folderNames = append(folderNames, folder.Name)
+		}
+		if !reflect.DeepEqual(names, tc.expected) || !reflect.DeepEqual(folderNames, tc.expectedFolders) {
+			t.Errorf("With order %q, expected %q and %q, got %q and %q", tc.order, tc.expected, tc.expectedFolders, names, folderNames)
+		}
+	}
+}
+
+func TestSortCollectionPinnedOrder(t *testing.T) {
+	// Given
+	builder := &CollectionBuilder{}
+	col := Collection{
+		Name: "Cats API",
+		Meta: Meta{"order": []interface{}{"Dogs", "Getting started"}},
+		Folders: []Folder{
+			{Name: "Birds"},
+			{Name: "Getting started"},
+			{Name: "Dogs", Meta: Meta{"order": "Create a dog, Get all dogs, Walk a dog"}, Requests: []Request{
+				{Name: "Delete a dog", Method: "DELETE"},
+				{Name: "Get all dogs", Method: "GET"},
+				{Name: "Create a dog", Method: "POST"},
+				{Name: "Get one dog", Method: "GET"},
+			}},
+		},
+	}
+
+	// When
+	err := builder.sortCollection(&col, SortAlpha)
+
+	// Then
+	if err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	folderNames := []string{col.Folders[0].Name, col.Folders[1].Name, col.Folders[2].Name}
+	if !reflect.DeepEqual(folderNames, []string{"Dogs", "Getting started", "Birds"}) {
+		t.Errorf("The folders were not pinned, got %q", folderNames)
+	}
+	names := make([]string, 0)
+	for _, request := range col.Folders[0].Requests {
+		names = append(names, request.Name)
+	}
+	if !reflect.DeepEqual(names, []string{"Create a dog", "Get all dogs", "Delete a dog", "Get one dog"}) {
+		t.Errorf("The requests were not pinned, got %q", names)
+	}
+	if !reflect.DeepEqual(col.Warnings, []string{"Dogs: order refers to unknown item Walk a dog"}) {
+		t.Errorf("Expected a warning about the unknown item, got %q", col.Warnings)
+	}
+}
+
+func TestSortCollectionUnknownOrder(t *testing.T) {
+	err := (&CollectionBuilder{}).sortCollection(&Collection{}, "random")
+
+	if err == nil || err.Error() != "unknown sort order random, expected alpha, method, path or none" {
+		t.Errorf("Expected an error about the unknown order, got %v", err)
+	}
+}
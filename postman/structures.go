@@ -0,0 +1,477 @@
+package postman
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	yaml "gopkg.in/yaml.v2"
+)
+
+var descriptionStructurePattern = regexp.MustCompile("(?s)```postmanerator-structure[ \t]*\r?\n(.*?)\r?\n?```[ \t]*(\r?\n)?")
+
+// sourcedStructure is a structure along with a description of where it was defined, for warnings.
+type sourcedStructure struct {
+	StructureDefinition
+	source string
+}
+
+func withSource(source string, structures []StructureDefinition) []sourcedStructure {
+	sourced := make([]sourcedStructure, 0, len(structures))
+	for _, structure := range structures {
+		sourced = append(sourced, sourcedStructure{StructureDefinition: structure, source: source})
+	}
+	return sourced
+}
+
+// object is a decoded YAML, JSON or Javascript object that remembers the order of its keys.
+type object struct {
+	keys   []string
+	values map[string]interface{}
+}
+
+func (o object) get(key string) (interface{}, bool) {
+	value, ok := o.values[key]
+	return value, ok
+}
+
+func (o object) getString(key string) string {
+	if value, ok := o.values[key].(string); ok {
+		return value
+	}
+	return ""
+}
+
+// normalize converts the maps decoded by the YAML package or exported by otto into objects.
+func normalize(value interface{}) interface{} {
+	switch v := value.(type) {
+	case yaml.MapSlice:
+		o := object{keys: make([]string, 0, len(v)), values: map[string]interface{}{}}
+		for _, item := range v {
+			key := fmt.Sprint(item.Key)
+			o.keys = append(o.keys, key)
+			o.values[key] = normalize(item.Value)
+		}
+		return o
+	case map[string]interface{}:
+		o := object{keys: make([]string, 0, len(v)), values: map[string]interface{}{}}
+		for key, item := range v {
+			o.keys = append(o.keys, key)
+			o.values[key] = normalize(item)
+		}
+		sort.Strings(o.keys)
+		return o
+	case map[interface{}]interface{}:
+		m := map[string]interface{}{}
+		for key, item := range v {
+			m[fmt.Sprint(key)] = item
+		}
+		return normalize(m)
+	case []yaml.MapSlice:
+		items := make([]interface{}, 0, len(v))
+		for _, item := range v {
+			items = append(items, normalize(item))
+		}
+		return items
+	case []map[string]interface{}:
+		items := make([]interface{}, 0, len(v))
+		for _, item := range v {
+			items = append(items, normalize(item))
+		}
+		return items
+	case []interface{}:
+		items := make([]interface{}, 0, len(v))
+		for _, item := range v {
+			items = append(items, normalize(item))
+		}
+		return items
+	}
+	return value
+}
+
+// structureReader builds structures from their native or JSON Schema definitions, and collects warnings about
+// the attributes it does not understand.
+type structureReader struct {
+	warnings []string
+}
+
+func (r *structureReader) warnf(format string, args ...interface{}) {
+	r.warnings = append(r.warnings, fmt.Sprintf(format, args...))
+}
+
+// parseDocument reads structures written in YAML or JSON, either in the native format, which is
+// a structure, a list of structures or a "structures" list, or as JSON Schema "definitions" or "$defs".
+func (r *structureReader) parseDocument(contents []byte) ([]StructureDefinition, error) {
+	document, err := decodeOrdered(contents)
+	if err != nil {
+		return nil, err
+	}
+
+	switch doc := document.(type) {
+	case []interface{}:
+		return r.fromList(doc)
+	case object:
+		if structures, ok := doc.get("structures"); ok {
+			list, ok := structures.([]interface{})
+			if !ok {
+				return nil, errors.New("structures attribute must be an array of objects")
+			}
+			return r.fromList(list)
+		}
+		for _, key := range []string{"definitions", "$defs"} {
+			if definitions, ok := doc.get(key); ok {
+				defs, ok := definitions.(object)
+				if !ok {
+					return nil, fmt.Errorf("%v attribute must be an object", key)
+				}
+				return r.fromJSONSchemaDefinitions(defs)
+			}
+		}
+		if _, ok := doc.get("properties"); ok {
+			structure, err := r.fromJSONSchema(doc.getString("title"), doc)
+			return []StructureDefinition{structure}, err
+		}
+		structure, err := r.fromObject(doc)
+		return []StructureDefinition{structure}, err
+	}
+	return nil, errors.New("structures must be defined as an object or an array of objects")
+}
+
+// decodeOrdered decodes a YAML or JSON document, keeping the order of the keys of its objects when possible.
+func decodeOrdered(contents []byte) (interface{}, error) {
+	var document interface{}
+	if err := yaml.Unmarshal(contents, &document); err != nil {
+		return nil, err
+	}
+	switch document.(type) {
+	case map[interface{}]interface{}:
+		var mapping yaml.MapSlice
+		if err := yaml.Unmarshal(contents, &mapping); err == nil {
+			return normalize(mapping), nil
+		}
+	case []interface{}:
+		var list []yaml.MapSlice
+		if err := yaml.Unmarshal(contents, &list); err == nil {
+			return normalize(list), nil
+		}
+	}
+	return normalize(document), nil
+}
+
+func (r *structureReader) fromList(list []interface{}) ([]StructureDefinition, error) {
+	structures := make([]StructureDefinition, 0, len(list))
+	for _, item := range list {
+		o, ok := item.(object)
+		if !ok {
+			return structures, errors.New("structures must be objects")
+		}
+		structure, err := r.fromObject(o)
+		if err != nil {
+			return structures, err
+		}
+		structures = append(structures, structure)
+	}
+	return structures, nil
+}
+
+// fromObject builds a structure from its native definition, as written in Javascript, YAML or JSON.
+func (r *structureReader) fromObject(src object) (StructureDefinition, error) {
+	var structDef StructureDefinition
+
+	name, ok := src.get("name")
+	if !ok || name == nil {
+		return structDef, errors.New("structures must have a name")
+	}
+	structDef.Name = fmt.Sprint(name)
+	structDef.Description = src.getString("description")
+
+	fields, ok := src.get("fields")
+	if !ok {
+		return structDef, errors.New("structures must define fields")
+	}
+	fieldDefs, err := r.fields(structDef.Name, "", fields)
+	if err != nil {
+		return structDef, err
+	}
+	structDef.Fields = fieldDefs
+
+	for _, key := range src.keys {
+		switch key {
+		case "name", "description", "fields":
+		default:
+			r.warnf("structure %v: unknown attribute %q", structDef.Name, key)
+		}
+	}
+
+	return structDef, nil
+}
+
+func (r *structureReader) fields(structure, parent string, value interface{}) ([]StructureFieldDefinition, error) {
+	list, ok := value.([]interface{})
+	if !ok {
+		return nil, errors.New("fields attribute must be an array of objects")
+	}
+	var fields []StructureFieldDefinition
+	for _, item := range list {
+		src, ok := item.(object)
+		if !ok {
+			return fields, errors.New("fields attribute must be an array of objects")
+		}
+		name, ok := src.get("name")
+		if !ok || name == nil {
+			return fields, errors.New("structure fields must have a name")
+		}
+		path := fmt.Sprint(name)
+		if parent != "" {
+			path = parent + "." + path
+		}
+		field, err := r.field(structure, path, src)
+		if err != nil {
+			return fields, err
+		}
+		fields = append(fields, field)
+	}
+	return fields, nil
+}
+
+// field builds a field from its native definition. The definition of the items of an array has no name.
+func (r *structureReader) field(structure, path string, src object) (StructureFieldDefinition, error) {
+	var field StructureFieldDefinition
+	context := fmt.Sprintf("structure %v, field %v", structure, path)
+	for _, key := range src.keys {
+		value := src.values[key]
+		switch key {
+		case "name":
+			field.Name = fmt.Sprint(value)
+		case "description":
+			field.Description = r.stringAttribute(context, key, value)
+		case "type":
+			field.Type = r.stringAttribute(context, key, value)
+		case "format":
+			field.Format = r.stringAttribute(context, key, value)
+		case "required":
+			field.Required = r.boolAttribute(context, key, value)
+		case "nullable":
+			field.Nullable = r.boolAttribute(context, key, value)
+		case "enum":
+			if values, ok := plain(value).([]interface{}); ok {
+				field.Enum = values
+			} else {
+				r.warnf("%v: enum attribute must be an array", context)
+			}
+		case "example":
+			field.Example = plain(value)
+		case "default":
+			field.Default = plain(value)
+		case "fields":
+			fields, err := r.fields(structure, path, value)
+			if err != nil {
+				return field, err
+			}
+			field.Fields = fields
+		case "items":
+			items, err := r.items(structure, path, value)
+			if err != nil {
+				return field, err
+			}
+			field.Items = items
+		default:
+			r.warnf("%v: unknown attribute %q", context, key)
+		}
+	}
+	field.Type = implicitType(field)
+	return field, nil
+}
+
+// items reads the definition of the items of an array, which is either a type or a field definition without a name.
+func (r *structureReader) items(structure, path string, value interface{}) (*StructureFieldDefinition, error) {
+	switch v := value.(type) {
+	case string:
+		return &StructureFieldDefinition{Type: v}, nil
+	case object:
+		items, err := r.field(structure, path+"[]", v)
+		return &items, err
+	}
+	r.warnf("structure %v, field %v: items attribute must be a type or an object", structure, path)
+	return nil, nil
+}
+
+func (r *structureReader) stringAttribute(context, key string, value interface{}) string {
+	s, ok := value.(string)
+	if !ok && value != nil {
+		r.warnf("%v: %v attribute must be a string", context, key)
+	}
+	return s
+}
+
+func (r *structureReader) boolAttribute(context, key string, value interface{}) bool {
+	b, ok := value.(bool)
+	if !ok {
+		r.warnf("%v: %v attribute must be a boolean", context, key)
+	}
+	return b
+}
+
+func (r *structureReader) fromJSONSchemaDefinitions(definitions object) ([]StructureDefinition, error) {
+	structures := make([]StructureDefinition, 0, len(definitions.keys))
+	for _, name := range definitions.keys {
+		schema, ok := definitions.values[name].(object)
+		if !ok {
+			return structures, fmt.Errorf("definition %v must be an object", name)
+		}
+		structure, err := r.fromJSONSchema(name, schema)
+		if err != nil {
+			return structures, err
+		}
+		structures = append(structures, structure)
+	}
+	return structures, nil
+}
+
+func (r *structureReader) fromJSONSchema(name string, schema object) (StructureDefinition, error) {
+	if title := schema.getString("title"); title != "" {
+		name = title
+	}
+	if name == "" {
+		return StructureDefinition{}, errors.New("structures must have a name")
+	}
+	structDef := StructureDefinition{Name: name, Description: schema.getString("description")}
+
+	properties, _ := schema.get("properties")
+	props, ok := properties.(object)
+	if !ok {
+		return structDef, fmt.Errorf("definition %v must have properties", name)
+	}
+	fields, err := r.jsonSchemaProperties(name, props, schema)
+	structDef.Fields = fields
+	return structDef, err
+}
+
+func (r *structureReader) jsonSchemaProperties(context string, properties object, schema object) ([]StructureFieldDefinition, error) {
+	required := map[string]bool{}
+	if list, ok := schema.values["required"].([]interface{}); ok {
+		for _, name := range list {
+			required[fmt.Sprint(name)] = true
+		}
+	}
+
+	var fields []StructureFieldDefinition
+	for _, key := range properties.keys {
+		property, ok := properties.values[key].(object)
+		if !ok {
+			return fields, fmt.Errorf("property %v of %v must be an object", key, context)
+		}
+		field, err := r.jsonSchemaField(context+"."+key, property)
+		if err != nil {
+			return fields, err
+		}
+		field.Name = key
+		field.Required = required[key]
+		fields = append(fields, field)
+	}
+	return fields, nil
+}
+
+// jsonSchemaField builds a field from a JSON Schema property. The keywords that have no equivalent are ignored.
+func (r *structureReader) jsonSchemaField(context string, property object) (StructureFieldDefinition, error) {
+	field := StructureFieldDefinition{
+		Description: property.getString("description"),
+		Format:      property.getString("format"),
+		Example:     plain(property.values["example"]),
+		Default:     plain(property.values["default"]),
+	}
+	field.Type, field.Nullable = jsonSchemaType(property)
+	if nullable, ok := property.values["nullable"].(bool); ok {
+		field.Nullable = field.Nullable || nullable
+	}
+	if enum, ok := plain(property.values["enum"]).([]interface{}); ok {
+		field.Enum = enum
+	}
+	if examples, ok := plain(property.values["examples"]).([]interface{}); ok && len(examples) > 0 && field.Example == nil {
+		field.Example = examples[0]
+	}
+	if properties, ok := property.values["properties"].(object); ok {
+		fields, err := r.jsonSchemaProperties(context, properties, property)
+		if err != nil {
+			return field, err
+		}
+		field.Fields = fields
+	}
+	if items, ok := property.values["items"].(object); ok {
+		itemsField, err := r.jsonSchemaField(context+"[]", items)
+		if err != nil {
+			return field, err
+		}
+		field.Items = &itemsField
+	}
+	field.Type = implicitType(field)
+	return field, nil
+}
+
+// jsonSchemaType returns the type of a JSON Schema property, or the name of the definition it refers to,
+// and whether the type allows null values.
+func jsonSchemaType(property object) (string, bool) {
+	if ref := property.getString("$ref"); ref != "" {
+		return ref[strings.LastIndex(ref, "/")+1:], false
+	}
+	types, ok := property.values["type"].([]interface{})
+	if !ok {
+		return property.getString("type"), false
+	}
+	nullable := false
+	names := make([]string, 0, len(types))
+	for _, t := range types {
+		if t == "null" {
+			nullable = true
+			continue
+		}
+		names = append(names, fmt.Sprint(t))
+	}
+	return strings.Join(names, "|"), nullable
+}
+
+// implicitType returns the type of a field, which defaults to object or array when the field has nested fields or items.
+func implicitType(field StructureFieldDefinition) string {
+	switch {
+	case field.Type != "":
+		return field.Type
+	case field.Fields != nil:
+		return "object"
+	case field.Items != nil:
+		return "array"
+	}
+	return ""
+}
+
+// plain converts objects back into maps, so that examples and default values can be used by themes.
+func plain(value interface{}) interface{} {
+	switch v := value.(type) {
+	case object:
+		m := make(map[string]interface{}, len(v.keys))
+		for key, item := range v.values {
+			m[key] = plain(item)
+		}
+		return m
+	case []interface{}:
+		items := make([]interface{}, 0, len(v))
+		for _, item := range v {
+			items = append(items, plain(item))
+		}
+		return items
+	}
+	return value
+}
+
+// extractDescriptionStructures returns a description without its structure blocks, and the contents of these blocks.
+func extractDescriptionStructures(description string) (string, []string) {
+	blocks := make([]string, 0)
+	for _, match := range descriptionStructurePattern.FindAllStringSubmatch(description, -1) {
+		blocks = append(blocks, match[1])
+	}
+	if len(blocks) == 0 {
+		return description, blocks
+	}
+	return strings.TrimSpace(descriptionStructurePattern.ReplaceAllString(description, "")), blocks
+}
@@ -0,0 +1,326 @@
+package postman
+
+import (
+	"io/ioutil"
+	"os"
+	"reflect"
+	"testing"
+)
+
+func TestParseStructuresDocument(t *testing.T) {
+	expectedCat := StructureDefinition{Name: "Cat", Description: "A great animal", Fields: []StructureFieldDefinition{
+		{Name: "id", Description: "A unique identifier for the cat", Type: "int"},
+		{Name: "owner", Description: "The owner of the cat", Type: "Person"},
+	}}
+	expectedPerson := StructureDefinition{Name: "Person", Fields: []StructureFieldDefinition{
+		{Name: "name", Type: "string"},
+	}}
+
+	testCases := []struct {
+		name     string
+		document string
+		expected []StructureDefinition
+	}{
+		{
+			name: "native YAML list",
+			document: `
+- name: Cat
+  description: A great animal
+  fields:
+    - {name: id, description: A unique identifier for the cat, type: int}
+    - {name: owner, description: The owner of the cat, type: Person}
+- name: Person
+  fields:
+    - name: name
+      type: string
+`,
+			expected: []StructureDefinition{expectedCat, expectedPerson},
+		},
+		{
+			name:     "native JSON structures attribute",
+			document: `{"structures": [{"name": "Person", "fields": [{"name": "name", "type": "string"}]}]}`,
+			expected: []StructureDefinition{expectedPerson},
+		},
+		{
+			name: "JSON Schema definitions",
+			document: `{
+	"definitions": {
+		"Cat": {
+			"description": "A great animal",
+			"properties": {
+				"id": {"type": "int", "description": "A unique identifier for the cat"},
+				"owner": {"$ref": "#/definitions/Person", "description": "The owner of the cat"}
+			}
+		},
+		"Person": {"properties": {"name": {"type": "string"}}}
+	}
+}`,
+			expected: []StructureDefinition{expectedCat, expectedPerson},
+		},
+		{
+			name:     "structure without fields",
+			document: "name: Empty\nfields: []",
+			expected: []StructureDefinition{{Name: "Empty"}},
+		},
+	}
+
+	for _, tc := range testCases {
+		// When
+		structures, err := (&structureReader{}).parseDocument([]byte(tc.document))
+
+		// Then
+		if err != nil {
+			t.Errorf("%v: unexpected error: %v", tc.name, err)
+		}
+		if !reflect.DeepEqual(structures, tc.expected) {
+			t.Errorf("%v: expected %v, got %v", tc.name, tc.expected, structures)
+		}
+	}
+}
+
+func TestParseStructuresDocumentErrors(t *testing.T) {
+	testCases := []struct {
+		document string
+		expected string
+	}{
+		{document: "description: no name\nfields: []", expected: "structures must have a name"},
+		{document: "name: Cat", expected: "structures must define fields"},
+		{document: "name: Cat\nfields: [{type: int}]", expected: "structure fields must have a name"},
+		{document: "definitions: {Cat: {type: object}}", expected: "definition Cat must have properties"},
+	}
+
+	for _, tc := range testCases {
+		// When
+		_, err := (&structureReader{}).parseDocument([]byte(tc.document))
+
+		// Then
+		if err == nil || err.Error() != tc.expected {
+			t.Errorf("Expected error %q for %q, got %v", tc.expected, tc.document, err)
+		}
+	}
+}
+
+func TestExtractDescriptionStructures(t *testing.T) {
+	// Given
+	description := "Everything about cats.\n\n```postmanerator-structure\nname: Cat\nfields: []\n```\n\nEnjoy."
+
+	// When
+	stripped, blocks := extractDescriptionStructures(description)
+
+	// Then
+	if stripped != "Everything about cats.\n\n\nEnjoy." {
+		t.Errorf("Structure blocks were not removed from the description, got %q", stripped)
+	}
+	if !reflect.DeepEqual(blocks, []string{"name: Cat\nfields: []"}) {
+		t.Errorf("Structure blocks were not properly extracted, got %q", blocks)
+	}
+}
+
+func TestExtractStructuresDefinitionSources(t *testing.T) {
+	// Given
+	builder := &CollectionBuilder{}
+	file, err := ioutil.TempFile("", "postmanerator-structures")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(file.Name())
+	file.WriteString("- name: Cat\n  description: From the file\n  fields: []\n")
+	file.Close()
+	col := Collection{
+		Name:        "Cats API",
+		Description: "```postmanerator-structure\nname: Cat\ndescription: From the collection\nfields: []\n```",
+		Folders: []Folder{{
+			Name:        "Dogs",
+			Description: "All the dogs.\n```postmanerator-structure\n- name: Dog\n  fields: []\n- invalid\n```",
+		}},
+		Requests: []Request{{Name: "Get all dogs", Tests: `/*[[start postmanerator]]*/
+function populateNewAPIStructures() {
+    APIStructures['dog'] = {name: 'Dog', description: 'From a script', fields: []};
+    APIStructures['bird'] = {name: 'Bird', description: 'From a script', fields: []};
+}
+/*[[end postmanerator]]*/`}},
+	}
+
+	// When
+	err = builder.extractStructuresDefinition(&col, BuilderOptions{StructuresFile: file.Name()})
+
+	// Then
+	if err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	expectedStructures := []StructureDefinition{
+		{Name: "Cat", Description: "From the file"},
+		{Name: "Dog"},
+		{Name: "Bird", Description: "From a script"},
+	}
+	if !reflect.DeepEqual(col.Structures, expectedStructures) {
+		t.Errorf("Structures were not properly merged, expected %v, got %v", expectedStructures, col.Structures)
+	}
+	expectedWarnings := []string{
+		"Dogs description: invalid structure block: structures must be objects",
+		"Cats API description: structure Cat is already defined in " + file.Name(),
+		"Get all dogs tests: structure Dog is already defined in Dogs description",
+	}
+	if !reflect.DeepEqual(col.Warnings, expectedWarnings) {
+		t.Errorf("Duplicated structures were not reported, expected %q, got %q", expectedWarnings, col.Warnings)
+	}
+	if col.Description != "" || col.Folders[0].Description != "All the dogs." {
+		t.Errorf("Structure blocks were not removed from the descriptions, got %q and %q", col.Description, col.Folders[0].Description)
+	}
+}
+
+func TestExtractStructuresDefinitionMissingFile(t *testing.T) {
+	// Given
+	builder := &CollectionBuilder{}
+
+	// When
+	err := builder.extractStructuresDefinition(&Collection{}, BuilderOptions{StructuresFile: "tests_data/missing.yml"})
+
+	// Then
+	if err == nil {
+		t.Error("Expected an error when the structures file does not exist")
+	}
+}
+
+func TestParseStructuresDocumentFieldAttributes(t *testing.T) {
+	// Given
+	document := `
+name: Book
+fields:
+  - name: id
+    type: string
+    format: uuid
+    required: true
+    example: 0b0f4c7e-0b1a-4c2f-9d1a-5a3b1f6e2c4d
+  - name: status
+    type: string
+    enum: [draft, published]
+    default: draft
+  - name: author
+    type: Author
+    nullable: true
+  - name: tags
+    items: string
+  - name: reviews
+    items:
+      fields:
+        - {name: rating, type: int, required: true}
+  - name: metadata
+    fields:
+      - {name: pages, type: int, example: 320}
+`
+	expected := []StructureDefinition{{Name: "Book", Fields: []StructureFieldDefinition{
+		{Name: "id", Type: "string", Format: "uuid", Required: true, Example: "0b0f4c7e-0b1a-4c2f-9d1a-5a3b1f6e2c4d"},
+		{Name: "status", Type: "string", Enum: []interface{}{"draft", "published"}, Default: "draft"},
+		{Name: "author", Type: "Author", Nullable: true},
+		{Name: "tags", Type: "array", Items: &StructureFieldDefinition{Type: "string"}},
+		{Name: "reviews", Type: "array", Items: &StructureFieldDefinition{Type: "object", Fields: []StructureFieldDefinition{
+			{Name: "rating", Type: "int", Required: true},
+		}}},
+		{Name: "metadata", Type: "object", Fields: []StructureFieldDefinition{
+			{Name: "pages", Type: "int", Example: 320},
+		}},
+	}}}
+	reader := &structureReader{}
+
+	// When
+	structures, err := reader.parseDocument([]byte(document))
+
+	// Then
+	if err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(structures, expected) {
+		t.Errorf("Field attributes were not properly parsed, expected %+v, got %+v", expected, structures)
+	}
+	if len(reader.warnings) != 0 {
+		t.Errorf("Unexpected warnings: %q", reader.warnings)
+	}
+}
+
+func TestParseStructuresDocumentUnknownAttributes(t *testing.T) {
+	// Given
+	document := `
+name: Book
+colour: red
+fields:
+  - {name: id, type: string, reqired: true}
+  - {name: isbn, required: "yes"}
+  - name: author
+    fields:
+      - {name: name, maxLength: 50}
+`
+	reader := &structureReader{}
+
+	// When
+	_, err := reader.parseDocument([]byte(document))
+
+	// Then
+	if err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	expectedWarnings := []string{
+		`structure Book, field id: unknown attribute "reqired"`,
+		"structure Book, field isbn: required attribute must be a boolean",
+		`structure Book, field author.name: unknown attribute "maxLength"`,
+		`structure Book: unknown attribute "colour"`,
+	}
+	if !reflect.DeepEqual(reader.warnings, expectedWarnings) {
+		t.Errorf("Unknown attributes were not reported, expected %q, got %q", expectedWarnings, reader.warnings)
+	}
+}
+
+func TestParseStructuresDocumentJSONSchemaAttributes(t *testing.T) {
+	// Given
+	document := `{
+	"$defs": {
+		"Book": {
+			"required": ["id"],
+			"properties": {
+				"id": {"type": "string", "format": "uuid"},
+				"summary": {"type": ["string", "null"], "examples": ["A great book"]},
+				"status": {"type": "string", "enum": ["draft", "published"], "default": "draft"},
+				"authors": {"type": "array", "items": {"$ref": "#/$defs/Author"}},
+				"metadata": {"type": "object", "required": ["pages"], "properties": {"pages": {"type": "integer", "example": 320}}}
+			}
+		}
+	}
+}`
+	expected := []StructureDefinition{{Name: "Book", Fields: []StructureFieldDefinition{
+		{Name: "id", Type: "string", Format: "uuid", Required: true},
+		{Name: "summary", Type: "string", Nullable: true, Example: "A great book"},
+		{Name: "status", Type: "string", Enum: []interface{}{"draft", "published"}, Default: "draft"},
+		{Name: "authors", Type: "array", Items: &StructureFieldDefinition{Type: "Author"}},
+		{Name: "metadata", Type: "object", Fields: []StructureFieldDefinition{
+			{Name: "pages", Type: "integer", Required: true, Example: 320},
+		}},
+	}}}
+
+	// When
+	structures, err := (&structureReader{}).parseDocument([]byte(document))
+
+	// Then
+	if err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(structures, expected) {
+		t.Errorf("JSON Schema keywords were not properly converted, expected %+v, got %+v", expected, structures)
+	}
+}
+
+func TestFindStructure(t *testing.T) {
+	// Given
+	col := Collection{Structures: []StructureDefinition{{Name: "Book"}, {Name: "Author"}}}
+
+	// When
+	author := col.FindStructure("Author")
+	missing := col.FindStructure("Publisher")
+
+	// Then
+	if author == nil || author.Name != "Author" {
+		t.Errorf("Expected to find the Author structure, got %v", author)
+	}
+	if missing != nil {
+		t.Errorf("Expected no structure, got %v", missing)
+	}
+}
@@ -0,0 +1,35 @@
+package postman
+
+import "strings"
+
+// Path returns the path of the request URL, without the scheme and the host, or the variable standing for them such
+// as {{url}}, and without the query string and the fragment. It is "/" when the URL has no path.
+func (r Request) Path() string {
+	path, _ := splitURL(r.URL)
+	return path
+}
+
+// RequestURI returns the path of the request URL, followed by its query string and its fragment, if any.
+func (r Request) RequestURI() string {
+	path, query := splitURL(r.URL)
+	return path + query
+}
+
+// splitURL strips the scheme and the host of a collection URL, and splits what remains into the path, and the query
+// string followed by the fragment.
+func splitURL(rawURL string) (string, string) {
+	if i := strings.Index(rawURL, "://"); i >= 0 {
+		rawURL = rawURL[i+3:]
+	}
+	query := ""
+	if i := strings.IndexAny(rawURL, "?#"); i >= 0 {
+		rawURL, query = rawURL[:i], rawURL[i:]
+	}
+	if strings.HasPrefix(rawURL, "/") {
+		return rawURL, query
+	}
+	if i := strings.Index(rawURL, "/"); i >= 0 {
+		return rawURL[i:], query
+	}
+	return "/", query
+}
@@ -0,0 +1,36 @@
+package postman
+
+import "testing"
+
+func TestRequestPath(t *testing.T) {
+	testCases := []struct {
+		url                string
+		expectedPath       string
+		expectedRequestURI string
+	}{
+		{url: "{{url}}/cats/:id", expectedPath: "/cats/:id", expectedRequestURI: "/cats/:id"},
+		{url: "http://localhost:8080/cats?full=true", expectedPath: "/cats", expectedRequestURI: "/cats?full=true"},
+		{url: "https://{{host}}/api/cats#top", expectedPath: "/api/cats", expectedRequestURI: "/api/cats#top"},
+		{url: "localhost:8080/cats", expectedPath: "/cats", expectedRequestURI: "/cats"},
+		{url: "/cats/:id", expectedPath: "/cats/:id", expectedRequestURI: "/cats/:id"},
+		{url: "{{url}}", expectedPath: "/", expectedRequestURI: "/"},
+		{url: "{{url}}?page=2", expectedPath: "/", expectedRequestURI: "/?page=2"},
+		{url: "http://localhost/search?q=a/b", expectedPath: "/search", expectedRequestURI: "/search?q=a/b"},
+	}
+
+	for _, tc := range testCases {
+		// Given
+		request := Request{URL: tc.url}
+
+		// When
+		path, requestURI := request.Path(), request.RequestURI()
+
+		// Then
+		if path != tc.expectedPath {
+			t.Errorf("Expected path %q for %q, got %q", tc.expectedPath, tc.url, path)
+		}
+		if requestURI != tc.expectedRequestURI {
+			t.Errorf("Expected request URI %q for %q, got %q", tc.expectedRequestURI, tc.url, requestURI)
+		}
+	}
+}
@@ -0,0 +1,243 @@
+package recorder
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httputil"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/aubm/postmanerator/postman"
+)
+
+type requestBodyKey struct{}
+
+// skippedHeaders are managed by the transport and are not recorded. Content-Encoding is recorded, unless the body
+// could be decoded.
+var skippedHeaders = map[string]bool{
+	"Connection":        true,
+	"Content-Length":    true,
+	"Transfer-Encoding": true,
+	"Accept-Encoding":   true,
+	"Keep-Alive":        true,
+	"Upgrade":           true,
+	"X-Forwarded-For":   true,
+}
+
+// Recorder is a reverse proxy that records the requests it forwards and the responses of the upstream server.
+type Recorder struct {
+	upstream *url.URL
+	proxy    *httputil.ReverseProxy
+	mutex    sync.Mutex
+	groups   []*group
+}
+
+// group gathers the calls sharing a method and a path template.
+type group struct {
+	method    string
+	template  pathTemplate
+	responses []postman.Response
+	seen      map[string]bool
+}
+
+func NewRecorder(upstream *url.URL) *Recorder {
+	r := &Recorder{upstream: upstream}
+	r.proxy = httputil.NewSingleHostReverseProxy(upstream)
+	director := r.proxy.Director
+	r.proxy.Director = func(req *http.Request) {
+		director(req)
+		// The encodings accepted by the client, such as br or deflate, could not be decoded when recording: without
+		// Accept-Encoding, the transport asks for gzip and decodes the responses itself.
+		req.Header.Del("Accept-Encoding")
+	}
+	r.proxy.ModifyResponse = r.record
+	return r
+}
+
+func (r *Recorder) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	body, err := ioutil.ReadAll(req.Body)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to read the request body: %v", err), http.StatusBadRequest)
+		return
+	}
+	req.Body = ioutil.NopCloser(bytes.NewReader(body))
+	req = req.WithContext(context.WithValue(req.Context(), requestBodyKey{}, body))
+	r.proxy.ServeHTTP(w, req)
+}
+
+func (r *Recorder) record(res *http.Response) error {
+	body, err := ioutil.ReadAll(res.Body)
+	res.Body.Close()
+	if err != nil {
+		return err
+	}
+	res.Body = ioutil.NopCloser(bytes.NewReader(body))
+
+	responseHeader := res.Header
+	if res.Header.Get("Content-Encoding") == "gzip" {
+		if decoded, err := gunzip(body); err == nil {
+			body = decoded
+			responseHeader = copyHeader(res.Header)
+			responseHeader.Del("Content-Encoding")
+		}
+	}
+
+	req := res.Request
+	requestBody, _ := req.Context().Value(requestBodyKey{}).([]byte)
+	template := newPathTemplate(req.URL.Path)
+
+	originalRequest := r.buildRequest(req.Method, r.upstreamURL(req.URL.Path, req.URL.RawQuery), req.Header, requestBody)
+	originalRequest.Name = fmt.Sprintf("%d %v", res.StatusCode, http.StatusText(res.StatusCode))
+
+	response := postman.Response{
+		Name:            originalRequest.Name,
+		Status:          http.StatusText(res.StatusCode),
+		StatusCode:      res.StatusCode,
+		Body:            string(body),
+		Headers:         headers(responseHeader),
+		OriginalRequest: &originalRequest,
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	g := r.findGroup(req.Method, template)
+	key := fmt.Sprintf("%d %v %s", res.StatusCode, req.URL.RawQuery, requestBody)
+	if !g.seen[key] {
+		g.seen[key] = true
+		g.responses = append(g.responses, response)
+	}
+	return nil
+}
+
+func (r *Recorder) findGroup(method string, template pathTemplate) *group {
+	for _, g := range r.groups {
+		if g.method == method && g.template.path == template.path {
+			return g
+		}
+	}
+	g := &group{method: method, template: template, responses: make([]postman.Response, 0), seen: map[string]bool{}}
+	r.groups = append(r.groups, g)
+	return g
+}
+
+// Collection returns the recorded calls, with one request per method and path template,
+// in one folder per first path segment.
+func (r *Recorder) Collection() postman.Collection {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	collection := postman.Collection{
+		Name:     fmt.Sprintf("Recorded from %v", r.upstream.Host),
+		Requests: make([]postman.Request, 0),
+		Folders:  make([]postman.Folder, 0),
+	}
+	folderIndexes := map[string]int{}
+
+	for _, g := range r.groups {
+		first := g.responses[0].OriginalRequest
+		request := *first
+		request.Name = fmt.Sprintf("%v %v", g.method, g.template.path)
+		request.URL = r.upstreamURL(g.template.path, "")
+		request.PathVariables = g.template.variables
+		request.Responses = g.responses
+
+		folderName := g.template.folder(r.upstream.Path)
+		if folderName == "" {
+			collection.Requests = append(collection.Requests, request)
+			continue
+		}
+		i, ok := folderIndexes[folderName]
+		if !ok {
+			i = len(collection.Folders)
+			folderIndexes[folderName] = i
+			collection.Folders = append(collection.Folders, postman.Folder{Name: folderName, Requests: make([]postman.Request, 0)})
+		}
+		collection.Folders[i].Requests = append(collection.Folders[i].Requests, request)
+	}
+	return collection
+}
+
+func (r *Recorder) buildRequest(method, rawURL string, header http.Header, body []byte) postman.Request {
+	request := postman.Request{
+		Method:        method,
+		URL:           rawURL,
+		Headers:       headers(header),
+		PathVariables: make([]postman.KeyValuePair, 0),
+		PayloadParams: make([]postman.KeyValuePair, 0),
+	}
+	if len(body) == 0 {
+		return request
+	}
+
+	if strings.HasPrefix(header.Get("Content-Type"), "application/x-www-form-urlencoded") {
+		if values, err := url.ParseQuery(string(body)); err == nil {
+			request.PayloadType = "urlencoded"
+			keys := make([]string, 0, len(values))
+			for key := range values {
+				keys = append(keys, key)
+			}
+			sort.Strings(keys)
+			for _, key := range keys {
+				for _, value := range values[key] {
+					request.PayloadParams = append(request.PayloadParams, postman.KeyValuePair{Name: key, Key: key, Value: value})
+				}
+			}
+			return request
+		}
+	}
+
+	request.PayloadType = "raw"
+	request.PayloadRaw = string(body)
+	return request
+}
+
+// upstreamURL builds an upstream URL from a path that already includes the path of the upstream URL, if any.
+func (r *Recorder) upstreamURL(path, rawQuery string) string {
+	u := fmt.Sprintf("%v://%v%v", r.upstream.Scheme, r.upstream.Host, path)
+	if rawQuery != "" {
+		u += "?" + rawQuery
+	}
+	return u
+}
+
+func headers(header http.Header) []postman.KeyValuePair {
+	keys := make([]string, 0, len(header))
+	for key := range header {
+		if !skippedHeaders[key] {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	pairs := make([]postman.KeyValuePair, 0, len(keys))
+	for _, key := range keys {
+		for _, value := range header[key] {
+			pairs = append(pairs, postman.KeyValuePair{Name: key, Key: key, Value: value})
+		}
+	}
+	return pairs
+}
+
+// copyHeader copies a header, so that the recorded headers can differ from the ones sent to the client.
+func copyHeader(header http.Header) http.Header {
+	copied := make(http.Header, len(header))
+	for key, values := range header {
+		copied[key] = append([]string(nil), values...)
+	}
+	return copied
+}
+
+func gunzip(body []byte) ([]byte, error) {
+	reader, err := gzip.NewReader(bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+	return ioutil.ReadAll(reader)
+}
@@ -0,0 +1,178 @@
+package recorder_test
+
+import (
+	"compress/gzip"
+	"compress/zlib"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+
+	"github.com/aubm/postmanerator/postman"
+	. "github.com/aubm/postmanerator/recorder"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Recorder", func() {
+
+	var (
+		upstream *httptest.Server
+		proxy    *httptest.Server
+		recorder *Recorder
+	)
+
+	BeforeEach(func() {
+		upstream = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			body, _ := ioutil.ReadAll(r.Body)
+			switch {
+			case r.URL.Path == "/api/cats/404":
+				w.WriteHeader(404)
+				fmt.Fprint(w, `{"error":"not found"}`)
+			case r.URL.Path == "/api/compressed":
+				w.Header().Set("Content-Encoding", "gzip")
+				writer := gzip.NewWriter(w)
+				fmt.Fprint(writer, "hello")
+				writer.Close()
+			case r.URL.Path == "/api/negotiated":
+				if strings.Contains(r.Header.Get("Accept-Encoding"), "br") {
+					w.Header().Set("Content-Encoding", "br")
+					fmt.Fprint(w, "\x1b\x04\x00\xf8")
+					return
+				}
+				fmt.Fprint(w, "hello")
+			case r.URL.Path == "/api/deflated":
+				w.Header().Set("Content-Encoding", "deflate")
+				writer := zlib.NewWriter(w)
+				fmt.Fprint(writer, "hello")
+				writer.Close()
+			case r.Method == "POST":
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(201)
+				fmt.Fprint(w, string(body))
+			default:
+				w.Header().Set("Content-Type", "application/json")
+				fmt.Fprintf(w, `{"path":%q}`, r.URL.Path)
+			}
+		}))
+		recorder = NewRecorder(must(url.Parse(upstream.URL + "/api")).(*url.URL))
+		proxy = httptest.NewServer(recorder)
+	})
+
+	AfterEach(func() {
+		proxy.Close()
+		upstream.Close()
+	})
+
+	get := func(path string) string {
+		res := must(http.Get(proxy.URL + path)).(*http.Response)
+		defer res.Body.Close()
+		return string(must(ioutil.ReadAll(res.Body)).([]byte))
+	}
+
+	It("should forward the requests to the upstream server", func() {
+		Expect(get("/cats/1")).To(Equal(`{"path":"/api/cats/1"}`))
+	})
+
+	It("should group the calls by path template", func() {
+		get("/cats/1")
+		get("/cats/2?full=true")
+		get("/cats/404")
+		get("/cats/c3b3d6d2-3c8a-4f6b-9f4e-2b1c3e4d5f60/toys/7")
+		get("/")
+
+		collection := recorder.Collection()
+		Expect(collection.Name).To(Equal("Recorded from " + strings.TrimPrefix(upstream.URL, "http://")))
+		Expect(collection.Requests).To(HaveLen(1))
+		Expect(collection.Requests[0].Name).To(Equal("GET /api/"))
+		Expect(collection.Folders).To(HaveLen(1))
+		Expect(collection.Folders[0].Name).To(Equal("cats"))
+
+		requests := collection.Folders[0].Requests
+		Expect(requests).To(HaveLen(2))
+
+		Expect(requests[0].Name).To(Equal("GET /api/cats/:id"))
+		Expect(requests[0].URL).To(Equal(upstream.URL + "/api/cats/:id"))
+		Expect(requests[0].PathVariables).To(Equal([]postman.KeyValuePair{{Name: "id", Key: "id", Value: "1"}}))
+		Expect(requests[0].Responses).To(HaveLen(3))
+		Expect(requests[0].Responses[0].Name).To(Equal("200 OK"))
+		Expect(requests[0].Responses[0].Body).To(Equal(`{"path":"/api/cats/1"}`))
+		Expect(requests[0].Responses[0].Headers).To(ContainElement(postman.KeyValuePair{
+			Name: "Content-Type", Key: "Content-Type", Value: "application/json",
+		}))
+		Expect(requests[0].Responses[1].OriginalRequest.URL).To(Equal(upstream.URL + "/api/cats/2?full=true"))
+		Expect(requests[0].Responses[2].Name).To(Equal("404 Not Found"))
+		Expect(requests[0].Responses[2].StatusCode).To(Equal(404))
+
+		Expect(requests[1].Name).To(Equal("GET /api/cats/:id/toys/:id2"))
+		Expect(requests[1].PathVariables).To(Equal([]postman.KeyValuePair{
+			{Name: "id", Key: "id", Value: "c3b3d6d2-3c8a-4f6b-9f4e-2b1c3e4d5f60"},
+			{Name: "id2", Key: "id2", Value: "7"},
+		}))
+	})
+
+	It("should not record the same call twice", func() {
+		get("/cats/1")
+		get("/cats/2")
+		Expect(recorder.Collection().Folders[0].Requests[0].Responses).To(HaveLen(1))
+	})
+
+	It("should record the request bodies", func() {
+		res := must(http.Post(proxy.URL+"/cats", "application/json", strings.NewReader(`{"name":"Tom"}`))).(*http.Response)
+		res.Body.Close()
+		res = must(http.PostForm(proxy.URL+"/login", url.Values{"user": {"tom"}})).(*http.Response)
+		res.Body.Close()
+
+		folders := recorder.Collection().Folders
+		create := folders[0].Requests[0]
+		Expect(create.PayloadType).To(Equal("raw"))
+		Expect(create.PayloadRaw).To(Equal(`{"name":"Tom"}`))
+		Expect(create.Responses[0].StatusCode).To(Equal(201))
+		Expect(create.Responses[0].Body).To(Equal(`{"name":"Tom"}`))
+
+		login := folders[1].Requests[0]
+		Expect(login.PayloadType).To(Equal("urlencoded"))
+		Expect(login.PayloadParams).To(Equal([]postman.KeyValuePair{{Name: "user", Key: "user", Value: "tom"}}))
+	})
+
+	It("should record decompressed bodies", func() {
+		Expect(get("/compressed")).To(Equal("hello"))
+		response := recorder.Collection().Folders[0].Requests[0].Responses[0]
+		Expect(response.Body).To(Equal("hello"))
+		for _, header := range response.Headers {
+			Expect(header.Key).NotTo(Equal("Content-Encoding"))
+		}
+	})
+
+	It("should not forward the encodings accepted by the client", func() {
+		req := must(http.NewRequest("GET", proxy.URL+"/negotiated", nil)).(*http.Request)
+		req.Header.Set("Accept-Encoding", "gzip, deflate, br")
+		res := must(http.DefaultClient.Do(req)).(*http.Response)
+		res.Body.Close()
+
+		response := recorder.Collection().Folders[0].Requests[0].Responses[0]
+		Expect(response.Body).To(Equal("hello"))
+		for _, header := range response.Headers {
+			Expect(header.Key).NotTo(Equal("Content-Encoding"))
+		}
+	})
+
+	It("should keep the encoding of the bodies it cannot decode", func() {
+		get("/deflated")
+		response := recorder.Collection().Folders[0].Requests[0].Responses[0]
+		Expect(response.Body).NotTo(Equal("hello"))
+		Expect(response.Headers).To(ContainElement(postman.KeyValuePair{
+			Name: "Content-Encoding", Key: "Content-Encoding", Value: "deflate",
+		}))
+	})
+
+})
+
+func must(v interface{}, err error) interface{} {
+	if err != nil {
+		panic(err)
+	}
+	return v
+}
@@ -0,0 +1,60 @@
+package recorder
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/aubm/postmanerator/postman"
+)
+
+var (
+	numericSegment = regexp.MustCompile(`^[0-9]+$`)
+	uuidSegment    = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+)
+
+// pathTemplate is a request path where the numeric and UUID segments are replaced by path variables,
+// named ":id", ":id2", ":id3" and so on.
+type pathTemplate struct {
+	path      string
+	variables []postman.KeyValuePair
+}
+
+func newPathTemplate(path string) pathTemplate {
+	template := pathTemplate{variables: make([]postman.KeyValuePair, 0)}
+	segments := strings.Split(path, "/")
+	for i, segment := range segments {
+		if !numericSegment.MatchString(segment) && !uuidSegment.MatchString(segment) {
+			continue
+		}
+		name := "id"
+		if n := len(template.variables); n > 0 {
+			name = fmt.Sprintf("id%d", n+1)
+		}
+		template.variables = append(template.variables, postman.KeyValuePair{Name: name, Key: name, Value: segment})
+		segments[i] = ":" + name
+	}
+	template.path = strings.Join(segments, "/")
+	if template.path == "" {
+		template.path = "/"
+	}
+	return template
+}
+
+// folder returns the first segment of the template after the given prefix, if it is not a path variable.
+func (t pathTemplate) folder(prefix string) string {
+	path := t.path
+	if prefix = strings.TrimSuffix(prefix, "/"); prefix != "" && strings.HasPrefix(path, prefix+"/") {
+		path = strings.TrimPrefix(path, prefix)
+	}
+	for _, segment := range strings.Split(path, "/") {
+		if segment == "" {
+			continue
+		}
+		if strings.HasPrefix(segment, ":") {
+			return ""
+		}
+		return segment
+	}
+	return ""
+}
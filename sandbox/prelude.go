@@ -0,0 +1,394 @@
+package sandbox
+
+// prelude implements the subset of the Postman sandbox API used by most test scripts:
+// pm.test, pm.expect with the common chai assertions, pm.response, pm.request, the variable scopes
+// and the legacy tests object.
+const prelude = `
+var console = {log: function () {}, info: function () {}, warn: function () {}, error: function () {}};
+
+function AssertionError(message) {
+	this.name = 'AssertionError';
+	this.message = message;
+}
+AssertionError.prototype = Object.create(Error.prototype);
+
+function __format(value) {
+	if (value === undefined) {
+		return 'undefined';
+	}
+	if (typeof value === 'function') {
+		return 'function';
+	}
+	try {
+		return JSON.stringify(value);
+	} catch (e) {
+		return String(value);
+	}
+}
+
+function __typeOf(value) {
+	if (value === null) {
+		return 'null';
+	}
+	if (Array.isArray(value)) {
+		return 'array';
+	}
+	return typeof value;
+}
+
+function __deepEqual(a, b) {
+	if (a === b) {
+		return true;
+	}
+	if (__typeOf(a) !== __typeOf(b) || typeof a !== 'object' || a === null) {
+		return false;
+	}
+	var keysA = Object.keys(a), keysB = Object.keys(b);
+	if (keysA.length !== keysB.length) {
+		return false;
+	}
+	for (var i = 0; i < keysA.length; i++) {
+		if (!Object.prototype.hasOwnProperty.call(b, keysA[i]) || !__deepEqual(a[keysA[i]], b[keysA[i]])) {
+			return false;
+		}
+	}
+	return true;
+}
+
+function __headers(list) {
+	return {
+		get: function (name) {
+			for (var i = 0; i < list.length; i++) {
+				if (list[i].key.toLowerCase() === String(name).toLowerCase()) {
+					return list[i].value;
+				}
+			}
+			return undefined;
+		},
+		has: function (name) {
+			return this.get(name) !== undefined;
+		},
+		all: function () {
+			return list;
+		},
+		toObject: function () {
+			var object = {};
+			for (var i = 0; i < list.length; i++) {
+				object[list[i].key] = list[i].value;
+			}
+			return object;
+		}
+	};
+}
+
+function Assertion(value) {
+	this._value = value;
+	this._negate = false;
+	this._deep = false;
+}
+
+Assertion.prototype._assert = function (passed, message, negatedMessage) {
+	if (this._negate) {
+		passed = !passed;
+		message = negatedMessage;
+	}
+	if (!passed) {
+		throw new AssertionError(message);
+	}
+	return this;
+};
+
+['to', 'be', 'been', 'is', 'that', 'which', 'and', 'has', 'have', 'with', 'at', 'of', 'same', 'does', 'own'].forEach(function (chain) {
+	Object.defineProperty(Assertion.prototype, chain, {get: function () { return this; }});
+});
+
+Object.defineProperty(Assertion.prototype, 'not', {get: function () { this._negate = !this._negate; return this; }});
+Object.defineProperty(Assertion.prototype, 'deep', {get: function () { this._deep = true; return this; }});
+
+function __defineTerminal(name, check) {
+	Object.defineProperty(Assertion.prototype, name, {get: function () { return check.call(this); }});
+}
+
+__defineTerminal('ok', function () {
+	if (this._value instanceof __Response) {
+		return this._assert(this._value.code >= 200 && this._value.code < 300,
+			'expected response to have a successful status code but got ' + this._value.code,
+			'expected response not to have a successful status code');
+	}
+	return this._assert(!!this._value, 'expected ' + __format(this._value) + ' to be truthy',
+		'expected ' + __format(this._value) + ' to be falsy');
+});
+__defineTerminal('success', function () {
+	return this.ok;
+});
+__defineTerminal('json', function () {
+	var parsed = true;
+	try {
+		JSON.parse(this._value instanceof __Response ? this._value.text() : this._value);
+	} catch (e) {
+		parsed = false;
+	}
+	return this._assert(parsed, 'expected response body to be a valid JSON', 'expected response body not to be a valid JSON');
+});
+__defineTerminal('true', function () {
+	return this._assert(this._value === true, 'expected ' + __format(this._value) + ' to be true',
+		'expected ' + __format(this._value) + ' not to be true');
+});
+__defineTerminal('false', function () {
+	return this._assert(this._value === false, 'expected ' + __format(this._value) + ' to be false',
+		'expected ' + __format(this._value) + ' not to be false');
+});
+__defineTerminal('null', function () {
+	return this._assert(this._value === null, 'expected ' + __format(this._value) + ' to be null',
+		'expected ' + __format(this._value) + ' not to be null');
+});
+__defineTerminal('undefined', function () {
+	return this._assert(this._value === undefined, 'expected ' + __format(this._value) + ' to be undefined',
+		'expected ' + __format(this._value) + ' not to be undefined');
+});
+__defineTerminal('exist', function () {
+	return this._assert(this._value !== null && this._value !== undefined, 'expected ' + __format(this._value) + ' to exist',
+		'expected ' + __format(this._value) + ' not to exist');
+});
+__defineTerminal('empty', function () {
+	var value = this._value, empty;
+	if (typeof value === 'string' || Array.isArray(value)) {
+		empty = value.length === 0;
+	} else if (value !== null && typeof value === 'object') {
+		empty = Object.keys(value).length === 0;
+	} else {
+		empty = !value;
+	}
+	return this._assert(empty, 'expected ' + __format(value) + ' to be empty', 'expected ' + __format(value) + ' not to be empty');
+});
+
+Assertion.prototype.equal = function (expected) {
+	var equal = this._deep ? __deepEqual(this._value, expected) : this._value === expected;
+	return this._assert(equal, 'expected ' + __format(this._value) + ' to equal ' + __format(expected),
+		'expected ' + __format(this._value) + ' not to equal ' + __format(expected));
+};
+Assertion.prototype.equals = Assertion.prototype.equal;
+Assertion.prototype.eq = Assertion.prototype.equal;
+
+Assertion.prototype.eql = function (expected) {
+	return this._assert(__deepEqual(this._value, expected), 'expected ' + __format(this._value) + ' to deeply equal ' + __format(expected),
+		'expected ' + __format(this._value) + ' not to deeply equal ' + __format(expected));
+};
+
+Assertion.prototype.a = function (type) {
+	var actual = __typeOf(this._value);
+	type = String(type).toLowerCase();
+	return this._assert(actual === type, 'expected ' + __format(this._value) + ' to be a ' + type + ' but got ' + actual,
+		'expected ' + __format(this._value) + ' not to be a ' + type);
+};
+Assertion.prototype.an = Assertion.prototype.a;
+
+Assertion.prototype.include = function (expected) {
+	var value = this._value, included = false;
+	if (typeof value === 'string') {
+		included = value.indexOf(expected) >= 0;
+	} else if (Array.isArray(value)) {
+		for (var i = 0; i < value.length && !included; i++) {
+			included = value[i] === expected || __deepEqual(value[i], expected);
+		}
+	} else if (value !== null && typeof value === 'object' && expected !== null && typeof expected === 'object') {
+		included = true;
+		for (var key in expected) {
+			if (!__deepEqual(value[key], expected[key])) {
+				included = false;
+			}
+		}
+	}
+	return this._assert(included, 'expected ' + __format(value) + ' to include ' + __format(expected),
+		'expected ' + __format(value) + ' not to include ' + __format(expected));
+};
+Assertion.prototype.includes = Assertion.prototype.include;
+Assertion.prototype.contain = Assertion.prototype.include;
+Assertion.prototype.contains = Assertion.prototype.include;
+
+Assertion.prototype.property = function (name, expected) {
+	var value = this._value;
+	var has = value !== null && value !== undefined && Object(value).hasOwnProperty(name);
+	if (arguments.length < 2 || !has) {
+		return this._assert(has, 'expected ' + __format(value) + ' to have property ' + __format(name),
+			'expected ' + __format(value) + ' not to have property ' + __format(name));
+	}
+	var equal = this._deep ? __deepEqual(value[name], expected) : value[name] === expected;
+	return this._assert(equal, 'expected ' + __format(value) + ' to have property ' + __format(name) + ' of ' + __format(expected) + ' but got ' + __format(value[name]),
+		'expected ' + __format(value) + ' not to have property ' + __format(name) + ' of ' + __format(expected));
+};
+
+Assertion.prototype.keys = function () {
+	var expected = Array.isArray(arguments[0]) ? arguments[0] : Array.prototype.slice.call(arguments);
+	var value = this._value, has = value !== null && typeof value === 'object';
+	for (var i = 0; i < expected.length && has; i++) {
+		has = Object.prototype.hasOwnProperty.call(value, expected[i]);
+	}
+	return this._assert(has, 'expected ' + __format(value) + ' to have keys ' + __format(expected),
+		'expected ' + __format(value) + ' not to have keys ' + __format(expected));
+};
+Assertion.prototype.key = Assertion.prototype.keys;
+
+Assertion.prototype.lengthOf = function (expected) {
+	var length = this._value === null || this._value === undefined ? undefined : this._value.length;
+	return this._assert(length === expected, 'expected ' + __format(this._value) + ' to have a length of ' + expected + ' but got ' + length,
+		'expected ' + __format(this._value) + ' not to have a length of ' + expected);
+};
+
+function __defineComparison(names, compare, description) {
+	names.forEach(function (name) {
+		Assertion.prototype[name] = function (expected) {
+			return this._assert(compare(this._value, expected), 'expected ' + __format(this._value) + ' to be ' + description + ' ' + __format(expected),
+				'expected ' + __format(this._value) + ' not to be ' + description + ' ' + __format(expected));
+		};
+	});
+}
+__defineComparison(['above', 'gt', 'greaterThan'], function (a, b) { return a > b; }, 'above');
+__defineComparison(['below', 'lt', 'lessThan'], function (a, b) { return a < b; }, 'below');
+__defineComparison(['least', 'gte'], function (a, b) { return a >= b; }, 'at least');
+__defineComparison(['most', 'lte'], function (a, b) { return a <= b; }, 'at most');
+
+Assertion.prototype.oneOf = function (list) {
+	var found = false;
+	for (var i = 0; i < list.length && !found; i++) {
+		found = this._deep ? __deepEqual(list[i], this._value) : list[i] === this._value;
+	}
+	return this._assert(found, 'expected ' + __format(this._value) + ' to be one of ' + __format(list),
+		'expected ' + __format(this._value) + ' not to be one of ' + __format(list));
+};
+
+Assertion.prototype.match = function (pattern) {
+	return this._assert(pattern.test(this._value), 'expected ' + __format(this._value) + ' to match ' + pattern,
+		'expected ' + __format(this._value) + ' not to match ' + pattern);
+};
+
+Assertion.prototype.status = function (expected) {
+	var response = this._value;
+	if (typeof expected === 'number') {
+		return this._assert(response.code === expected, 'expected response to have status code ' + expected + ' but got ' + response.code,
+			'expected response not to have status code ' + expected);
+	}
+	return this._assert(response.status === expected, 'expected response to have status reason ' + __format(expected) + ' but got ' + __format(response.status),
+		'expected response not to have status reason ' + __format(expected));
+};
+
+Assertion.prototype.header = function (name, expected) {
+	var value = this._value.headers.get(name);
+	if (arguments.length < 2 || value === undefined) {
+		return this._assert(value !== undefined, 'expected response to have header ' + name,
+			'expected response not to have header ' + name);
+	}
+	return this._assert(value === expected, 'expected response to have header ' + name + ' with value ' + __format(expected) + ' but got ' + __format(value),
+		'expected response not to have header ' + name + ' with value ' + __format(expected));
+};
+
+Assertion.prototype.body = function (expected) {
+	var body = this._value.text();
+	if (arguments.length === 0) {
+		return this._assert(body !== '', 'expected response to have a body', 'expected response not to have a body');
+	}
+	var equal = typeof expected === 'string' ? body === expected : __deepEqual(this._value.json(), expected);
+	return this._assert(equal, 'expected response body to equal ' + __format(expected),
+		'expected response body not to equal ' + __format(expected));
+};
+
+Assertion.prototype.jsonBody = function (path, expected) {
+	var value = this._value.json();
+	if (arguments.length === 0) {
+		return this;
+	}
+	var segments = String(path).split('.');
+	for (var i = 0; i < segments.length; i++) {
+		value = value === null || value === undefined ? undefined : value[segments[i]];
+	}
+	if (arguments.length < 2) {
+		return this._assert(value !== undefined, 'expected response body to have property ' + path,
+			'expected response body not to have property ' + path);
+	}
+	return this._assert(__deepEqual(value, expected), 'expected response body property ' + path + ' to equal ' + __format(expected) + ' but got ' + __format(value),
+		'expected response body property ' + path + ' not to equal ' + __format(expected));
+};
+
+function __Response(data) {
+	this.code = data.code;
+	this.status = data.status;
+	this.responseTime = 0;
+	this.headers = __headers(data.headers);
+	this._body = data.body;
+}
+__Response.prototype.text = function () {
+	return this._body;
+};
+__Response.prototype.json = function () {
+	return JSON.parse(this._body);
+};
+Object.defineProperty(__Response.prototype, 'to', {get: function () { return new Assertion(this); }});
+
+function __variableScope() {
+	var values = {};
+	return {
+		get: function (key) { return values[key]; },
+		set: function (key, value) { values[key] = value; },
+		has: function (key) { return values.hasOwnProperty(key); },
+		unset: function (key) { delete values[key]; },
+		clear: function () { values = {}; },
+		toObject: function () { return values; }
+	};
+}
+
+var __responseData = JSON.parse(__responseJSON);
+var __requestData = JSON.parse(__requestJSON);
+
+var pm = {
+	test: function (name, fn) {
+		try {
+			fn(function () {});
+			__report(String(name), true, '');
+		} catch (e) {
+			__report(String(name), false, e && e.message !== undefined ? String(e.message) : String(e));
+		}
+	},
+	expect: function (value) {
+		return new Assertion(value);
+	},
+	response: new __Response(__responseData),
+	request: {
+		url: __requestData.url,
+		method: __requestData.method,
+		headers: __headers(__requestData.headers),
+		body: {mode: __requestData.bodyMode, raw: __requestData.body}
+	},
+	info: {requestName: __requestData.name, eventName: 'test', iteration: 0, iterationCount: 1},
+	environment: __variableScope(),
+	globals: __variableScope(),
+	collectionVariables: __variableScope(),
+	variables: __variableScope(),
+	iterationData: __variableScope()
+};
+pm.test.skip = function () {};
+
+var tests = {};
+var responseCode = {code: __responseData.code, name: __responseData.status, detail: __responseData.status};
+var responseBody = __responseData.body;
+var responseHeaders = pm.response.headers.toObject();
+var responseTime = 0;
+var request = {url: __requestData.url, method: __requestData.method, headers: pm.request.headers.toObject(), data: __requestData.body};
+var environment = {};
+var globals = {};
+var postman = {
+	setEnvironmentVariable: pm.environment.set,
+	getEnvironmentVariable: pm.environment.get,
+	clearEnvironmentVariable: pm.environment.unset,
+	setGlobalVariable: pm.globals.set,
+	getGlobalVariable: pm.globals.get,
+	clearGlobalVariable: pm.globals.unset,
+	setNextRequest: function () {}
+};
+`
+
+// epilogue reports the results of the legacy tests object.
+const epilogue = `
+for (var __name in tests) {
+	__report(__name, !!tests[__name], tests[__name] ? '' : 'expected a truthy value');
+}
+`
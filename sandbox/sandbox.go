@@ -0,0 +1,119 @@
+package sandbox
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/aubm/postmanerator/postman"
+	"github.com/aubm/postmanerator/utils"
+	"github.com/robertkrimen/otto"
+)
+
+const DefaultTimeout = 5 * time.Second
+
+// TestResult is the outcome of a single pm.test call, or of a single entry of the legacy tests object.
+type TestResult struct {
+	Name    string
+	Passed  bool
+	Message string
+}
+
+// Sandbox runs Postman test scripts against saved responses.
+type Sandbox struct {
+	Timeout time.Duration
+}
+
+type scriptKeyValue struct {
+	Key   string `json:"key"`
+	Value string `json:"value"`
+}
+
+type scriptResponse struct {
+	Code    int              `json:"code"`
+	Status  string           `json:"status"`
+	Headers []scriptKeyValue `json:"headers"`
+	Body    string           `json:"body"`
+}
+
+type scriptRequest struct {
+	Name     string           `json:"name"`
+	URL      string           `json:"url"`
+	Method   string           `json:"method"`
+	Headers  []scriptKeyValue `json:"headers"`
+	BodyMode string           `json:"bodyMode"`
+	Body     string           `json:"body"`
+}
+
+// Run executes the script against the given response, as if it was received for the given request.
+// The returned error is set when the script itself fails, for example because of a syntax error.
+func (s *Sandbox) Run(script string, request postman.Request, response postman.Response) ([]TestResult, error) {
+	results := make([]TestResult, 0)
+
+	vm := otto.New()
+	vm.Set("__report", func(call otto.FunctionCall) otto.Value {
+		passed, _ := call.Argument(1).ToBoolean()
+		results = append(results, TestResult{
+			Name:    call.Argument(0).String(),
+			Passed:  passed,
+			Message: call.Argument(2).String(),
+		})
+		return otto.UndefinedValue()
+	})
+
+	responseJSON, err := json.Marshal(newScriptResponse(response))
+	if err != nil {
+		return nil, err
+	}
+	requestJSON, err := json.Marshal(newScriptRequest(request))
+	if err != nil {
+		return nil, err
+	}
+	vm.Set("__responseJSON", string(responseJSON))
+	vm.Set("__requestJSON", string(requestJSON))
+
+	if _, err := vm.Run(prelude); err != nil {
+		return nil, fmt.Errorf("failed to initialize the sandbox: %v", err)
+	}
+
+	timeout := s.Timeout
+	if timeout == 0 {
+		timeout = DefaultTimeout
+	}
+	if _, err := utils.RunScript(vm, script, timeout); err != nil {
+		return results, err
+	}
+
+	if _, err := vm.Run(epilogue); err != nil {
+		return results, err
+	}
+	return results, nil
+}
+
+func newScriptResponse(response postman.Response) scriptResponse {
+	return scriptResponse{
+		Code:    response.StatusCode,
+		Status:  response.Status,
+		Headers: newScriptKeyValues(response.Headers),
+		Body:    response.CompleteBody(),
+	}
+}
+
+func newScriptRequest(request postman.Request) scriptRequest {
+	return scriptRequest{
+		Name:     request.Name,
+		URL:      request.URL,
+		Method:   request.Method,
+		Headers:  newScriptKeyValues(request.Headers),
+		BodyMode: request.PayloadType,
+		Body:     request.PayloadRaw,
+	}
+}
+
+func newScriptKeyValues(pairs []postman.KeyValuePair) []scriptKeyValue {
+	values := make([]scriptKeyValue, 0, len(pairs))
+	for _, pair := range pairs {
+		values = append(values, scriptKeyValue{Key: pair.Key, Value: fmt.Sprint(pair.Value)})
+	}
+	return values
+}
@@ -0,0 +1,217 @@
+package sandbox_test
+
+import (
+	"time"
+
+	"github.com/aubm/postmanerator/postman"
+	. "github.com/aubm/postmanerator/sandbox"
+	"github.com/aubm/postmanerator/utils"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Sandbox", func() {
+
+	var (
+		sandbox  *Sandbox
+		request  postman.Request
+		response postman.Response
+		script   string
+		results  []TestResult
+		err      error
+	)
+
+	BeforeEach(func() {
+		sandbox = &Sandbox{}
+		request = postman.Request{
+			Name:    "Get one cat",
+			Method:  "GET",
+			URL:     "http://my-api.com/cats/1",
+			Headers: []postman.KeyValuePair{{Key: "Accept", Value: "application/json"}},
+		}
+		response = postman.Response{
+			Name:       "OK",
+			Status:     "OK",
+			StatusCode: 200,
+			Headers:    []postman.KeyValuePair{{Key: "Content-Type", Value: "application/json"}},
+			Body:       `{"name":"Tom","age":3,"toys":["ball","mouse"],"owner":{"name":"Bob"}}`,
+		}
+	})
+
+	JustBeforeEach(func() {
+		results, err = sandbox.Run(script, request, response)
+	})
+
+	Context("with passing pm tests", func() {
+
+		BeforeEach(func() {
+			script = `
+pm.test("Status code is 200", function () {
+    pm.response.to.have.status(200);
+    pm.response.to.be.ok;
+    pm.response.to.be.json;
+    pm.response.to.have.header("content-type", "application/json");
+    pm.response.to.not.have.header("X-Missing");
+});
+pm.test("Body is a cat", function () {
+    var cat = pm.response.json();
+    pm.expect(cat).to.be.an("object");
+    pm.expect(cat.name).to.equal("Tom");
+    pm.expect(cat.name).to.be.a("string").and.not.be.empty;
+    pm.expect(cat).to.have.property("age", 3);
+    pm.expect(cat).to.have.keys("name", "age");
+    pm.expect(cat.age).to.be.above(1).and.below(10);
+    pm.expect(cat.toys).to.include("ball");
+    pm.expect(cat.toys).to.have.lengthOf(2);
+    pm.expect(cat.owner).to.eql({name: "Bob"});
+    pm.expect(cat.owner).to.deep.equal({name: "Bob"});
+    pm.expect(cat).to.deep.include({owner: {name: "Bob"}});
+    pm.expect(cat.name).to.match(/^T/);
+    pm.expect(cat.name).to.be.oneOf(["Tom", "Sam"]);
+    pm.expect(cat.missing).to.be.undefined;
+    pm.expect(null).to.be.null;
+    pm.expect(true).to.be.true;
+    pm.expect(pm.request.method).to.equal("GET");
+    pm.expect(pm.request.headers.get("Accept")).to.equal("application/json");
+    pm.environment.set("cat", cat.name);
+    pm.expect(pm.environment.get("cat")).to.equal("Tom");
+    console.log("this should not be printed");
+});`
+		})
+
+		It("should report them as passed", func() {
+			Expect(err).To(BeNil())
+			Expect(results).To(Equal([]TestResult{
+				{Name: "Status code is 200", Passed: true},
+				{Name: "Body is a cat", Passed: true},
+			}))
+		})
+
+	})
+
+	Context("when the response body is truncated", func() {
+
+		BeforeEach(func() {
+			response.FullBody = response.Body
+			response.Body = `{"name":"Tom","age":3,"toys":["ball","… 1 more item(s)"]` + "\n" + postman.TruncationMarker
+			script = `
+pm.test("Body is a complete cat", function () {
+    pm.expect(pm.response.json().toys).to.have.lengthOf(2);
+});`
+		})
+
+		It("should run the tests against the complete body", func() {
+			Expect(err).To(BeNil())
+			Expect(results).To(Equal([]TestResult{{Name: "Body is a complete cat", Passed: true}}))
+		})
+
+	})
+
+	Context("with failing pm tests", func() {
+
+		BeforeEach(func() {
+			script = `
+pm.test("Status code is 201", function () {
+    pm.response.to.have.status(201);
+});
+pm.test("Cat is named Sam", function () {
+    pm.expect(pm.response.json().name).to.equal("Sam");
+});
+pm.test("Cat has no toys", function () {
+    pm.expect(pm.response.json().toys).to.be.empty;
+});
+pm.test("Cat is not a string", function () {
+    pm.expect(pm.response.json().name).not.to.be.a("string");
+});
+pm.test("Script throws", function () {
+    undefinedFunction();
+});`
+		})
+
+		It("should report them as failed with a message", func() {
+			Expect(err).To(BeNil())
+			Expect(results).To(Equal([]TestResult{
+				{Name: "Status code is 201", Message: "expected response to have status code 201 but got 200"},
+				{Name: "Cat is named Sam", Message: `expected "Tom" to equal "Sam"`},
+				{Name: "Cat has no toys", Message: `expected ["ball","mouse"] to be empty`},
+				{Name: "Cat is not a string", Message: `expected "Tom" not to be a string`},
+				{Name: "Script throws", Message: "'undefinedFunction' is not defined"},
+			}))
+		})
+
+	})
+
+	Context("with legacy tests", func() {
+
+		BeforeEach(func() {
+			script = `
+var data = JSON.parse(responseBody);
+tests["Status code is 200"] = responseCode.code === 200;
+tests["Content-Type is set"] = !!postman.getResponseHeader || responseHeaders["Content-Type"] === "application/json";
+tests["Cat is named Sam"] = data.name === "Sam";`
+		})
+
+		It("should report the tests object", func() {
+			Expect(err).To(BeNil())
+			Expect(results).To(Equal([]TestResult{
+				{Name: "Status code is 200", Passed: true},
+				{Name: "Content-Type is set", Passed: true},
+				{Name: "Cat is named Sam", Message: "expected a truthy value"},
+			}))
+		})
+
+	})
+
+	Context("with a script error outside of a test", func() {
+
+		BeforeEach(func() {
+			script = `
+pm.test("First", function () {});
+var x = ;`
+		})
+
+		It("should return an error", func() {
+			Expect(err).NotTo(BeNil())
+			Expect(results).To(BeEmpty())
+		})
+
+	})
+
+	Context("with a script that never ends", func() {
+
+		BeforeEach(func() {
+			sandbox.Timeout = 50 * time.Millisecond
+			script = `
+pm.test("Passing", function () {});
+while (true) {}`
+		})
+
+		It("should be interrupted", func() {
+			Expect(err).To(Equal(utils.ErrScriptTimeout))
+			Expect(results).To(Equal([]TestResult{{Name: "Passing", Passed: true}}))
+		})
+
+	})
+
+	Context("with a structure definition fragment", func() {
+
+		BeforeEach(func() {
+			script = `
+/*[[start postmanerator]]*/
+function populateNewAPIStructures() {
+    APIStructures['cat'] = {name: 'Cat', description: 'A cat', fields: []};
+}
+/*[[end postmanerator]]*/
+pm.test("Status code is 200", function () {
+    pm.response.to.have.status(200);
+});`
+		})
+
+		It("should run the tests", func() {
+			Expect(err).To(BeNil())
+			Expect(results).To(Equal([]TestResult{{Name: "Status code is 200", Passed: true}}))
+		})
+
+	})
+
+})
@@ -0,0 +1,87 @@
+package themes
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+const (
+	assetsDirectory   = "assets"
+	fingerprintLength = 8
+)
+
+// assetPaths maps the path of each theme asset, relative to the assets directory,
+// to the path of its copy, relative to the root of the output.
+type assetPaths map[string]string
+
+func (r *Renderer) copyAssets(out Output, theme *Theme) (assetPaths, error) {
+	paths := make(assetPaths)
+	sources := theme.assetSources()
+	for _, asset := range theme.allAssets() {
+		outputPath, err := r.copyAsset(out, sources[asset], asset)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to copy asset %v: %v", asset, err)
+		}
+		paths[asset] = outputPath
+	}
+	return paths, nil
+}
+
+func (r *Renderer) copyAsset(out Output, themePath string, asset string) (string, error) {
+	src, err := os.Open(filepath.Join(themePath, assetsDirectory, filepath.FromSlash(asset)))
+	if err != nil {
+		return "", err
+	}
+	defer src.Close()
+
+	outputPath := path.Join(assetsDirectory, asset)
+	if r.config().FingerprintAssets {
+		fingerprint, err := r.fingerprint(src)
+		if err != nil {
+			return "", err
+		}
+		outputPath = r.fingerprintedPath(outputPath, fingerprint)
+	}
+
+	dest, err := out.Create(outputPath)
+	if err != nil {
+		return "", err
+	}
+	defer dest.Close()
+
+	if _, err := io.Copy(dest, src); err != nil {
+		return "", err
+	}
+	return outputPath, nil
+}
+
+func (r *Renderer) fingerprint(src io.ReadSeeker) (string, error) {
+	hash := sha256.New()
+	if _, err := io.Copy(hash, src); err != nil {
+		return "", err
+	}
+	if _, err := src.Seek(0, io.SeekStart); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(hash.Sum(nil))[:fingerprintLength], nil
+}
+
+func (r *Renderer) fingerprintedPath(file, fingerprint string) string {
+	ext := path.Ext(file)
+	return fmt.Sprintf("%s.%s%s", strings.TrimSuffix(file, ext), fingerprint, ext)
+}
+
+// unfingerprintedAssets is used when no output directory is involved, assets then keep their original paths.
+func unfingerprintedAssets(theme *Theme) assetPaths {
+	paths := make(assetPaths)
+	for _, asset := range theme.allAssets() {
+		paths[asset] = path.Join(assetsDirectory, asset)
+	}
+	return paths
+}
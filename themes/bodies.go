@@ -0,0 +1,105 @@
+package themes
+
+import (
+	"encoding/base64"
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/aubm/postmanerator/postman"
+)
+
+const bodiesDirectory = "bodies"
+
+var bodyFileExtensions = map[string]string{
+	"application/json":         ".json",
+	"application/xml":          ".xml",
+	"text/xml":                 ".xml",
+	"text/html":                ".html",
+	"text/csv":                 ".csv",
+	"text/plain":               ".txt",
+	"image/png":                ".png",
+	"image/jpeg":               ".jpg",
+	"image/gif":                ".gif",
+	"image/webp":               ".webp",
+	"image/svg+xml":            ".svg",
+	"application/pdf":          ".pdf",
+	"application/zip":          ".zip",
+	"application/octet-stream": ".bin",
+}
+
+// bodyPaths maps the ID of each truncated or binary response to the path of the file holding its complete body,
+// relative to the root of the output.
+type bodyPaths map[string]string
+
+// writeBodyFiles writes the complete bodies of the truncated and of the binary responses, so that pages can link to
+// them instead of embedding them.
+func (r *Renderer) writeBodyFiles(out Output, collection postman.Collection) (bodyPaths, error) {
+	paths := make(bodyPaths)
+	usedPaths := make(map[string]bool)
+	var writeFolder func(folders []postman.Folder, requests []postman.Request) error
+	writeFolder = func(folders []postman.Folder, requests []postman.Request) error {
+		for _, request := range requests {
+			for _, response := range request.Responses {
+				if !response.Truncated() && !response.IsBinary() {
+					continue
+				}
+				name := bodyFileName(usedPaths, request, response)
+				if err := writeBodyFile(out, name, response); err != nil {
+					return fmt.Errorf("Failed to write the body of response %v: %v", response.Name, err)
+				}
+				paths[response.ID] = name
+			}
+		}
+		for _, folder := range folders {
+			if err := writeFolder(folder.Folders, folder.Requests); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	return paths, writeFolder(collection.Folders, collection.Requests)
+}
+
+func bodyFileName(usedPaths map[string]bool, request postman.Request, response postman.Response) string {
+	slug := strings.Trim(helperSlugify(request.Name)+"-"+helperSlugify(response.Name), "-")
+	if slug == "" {
+		slug = "body"
+	}
+	ext, ok := bodyFileExtensions[response.ContentType]
+	if !ok {
+		ext = ".txt"
+		if response.IsBinary() {
+			ext = ".bin"
+		}
+	}
+	candidate := path.Join(bodiesDirectory, slug+ext)
+	for i := 2; usedPaths[candidate]; i++ {
+		candidate = path.Join(bodiesDirectory, fmt.Sprintf("%s-%d%s", slug, i, ext))
+	}
+	usedPaths[candidate] = true
+	return candidate
+}
+
+func writeBodyFile(out Output, name string, response postman.Response) error {
+	w, err := out.Create(name)
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(bodyBytes(response)); err != nil {
+		w.Close()
+		return err
+	}
+	return w.Close()
+}
+
+// bodyBytes returns the complete body of a response, binary bodies saved as base64 being decoded.
+func bodyBytes(response postman.Response) []byte {
+	body := response.CompleteBody()
+	if response.IsBinary() {
+		if decoded, err := base64.StdEncoding.DecodeString(strings.TrimSpace(body)); err == nil {
+			return decoded
+		}
+	}
+	return []byte(body)
+}
@@ -1,6 +1,10 @@
 package themes_test
 
-import "github.com/aubm/postmanerator/postman"
+import (
+	"time"
+
+	"github.com/aubm/postmanerator/postman"
+)
 
 var exampleCollection = postman.Collection{
 	Name:        "My Collection",
@@ -138,3 +142,22 @@ var exampleCollection = postman.Collection{
 		},
 	},
 }
+
+var (
+	pastSunset   = time.Date(2001, 1, 31, 0, 0, 0, 0, time.UTC)
+	futureSunset = time.Date(2999, 12, 31, 0, 0, 0, 0, time.UTC)
+)
+
+var lifecycleCollection = postman.Collection{Requests: []postman.Request{
+	{Name: "Get all cats", Lifecycle: postman.Lifecycle{Deprecated: true, Sunset: &pastSunset, ReplacedBy: "Get all cats v2"}},
+	{Name: "Get one cat", Lifecycle: postman.Lifecycle{Deprecated: true, Sunset: &futureSunset, ReplacedBy: "Get one cat v2"}},
+	{Name: "Get all cats v2", Method: "GET", URL: "/v2/cats", Lifecycle: postman.Lifecycle{Since: "v2.0"}},
+}}
+
+var bodiesCollection = postman.Collection{Requests: []postman.Request{{
+	Name: "Get all cats",
+	Responses: []postman.Response{
+		{ID: "cats", Name: "Cats", ContentType: "application/json", Body: `[{"name":"Felix"},"… 1 more item(s)"]`, FullBody: `[{"name": "Felix"}, {"name": "Tom"}]`},
+		{ID: "photo", Name: "Photo", ContentType: "image/png", Body: "iVBORw0KGgo="},
+	},
+}}}
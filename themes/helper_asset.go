@@ -0,0 +1,13 @@
+package themes
+
+import "fmt"
+
+func helperAsset(paths assetPaths) func(asset string) (string, error) {
+	return func(asset string) (string, error) {
+		outputPath, ok := paths[asset]
+		if !ok {
+			return "", fmt.Errorf("Asset %v not found in the theme", asset)
+		}
+		return outputPath, nil
+	}
+}
@@ -0,0 +1,22 @@
+package themes
+
+import (
+	"fmt"
+
+	"github.com/aubm/postmanerator/postman"
+)
+
+func helperBodyFile(paths bodyPaths) func(response interface{}) (string, error) {
+	return func(response interface{}) (string, error) {
+		switch r := response.(type) {
+		case postman.Response:
+			return paths[r.ID], nil
+		case *postman.Response:
+			if r == nil {
+				return "", nil
+			}
+			return paths[r.ID], nil
+		}
+		return "", fmt.Errorf("Failed to find the body file: unsupported response %v", response)
+	}
+}
@@ -0,0 +1,29 @@
+package themes
+
+import (
+	"encoding/base64"
+	"fmt"
+
+	"github.com/aubm/postmanerator/postman"
+)
+
+func helperDataURI(response interface{}) (string, error) {
+	var res postman.Response
+	switch r := response.(type) {
+	case postman.Response:
+		res = r
+	case *postman.Response:
+		if r == nil {
+			return "", nil
+		}
+		res = *r
+	default:
+		return "", fmt.Errorf("Failed to build data URI: unsupported response %v", response)
+	}
+
+	contentType := res.ContentType
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+	return fmt.Sprintf("data:%v;base64,%v", contentType, base64.StdEncoding.EncodeToString(bodyBytes(res))), nil
+}
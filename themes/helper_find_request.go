@@ -0,0 +1,17 @@
+package themes
+
+import (
+	"fmt"
+
+	"github.com/aubm/postmanerator/postman"
+)
+
+func helperFindRequest(collection interface{}, name string) (*postman.Request, error) {
+	switch c := collection.(type) {
+	case postman.Collection:
+		return c.FindRequestByName(name), nil
+	case PageData:
+		return c.FindRequestByName(name), nil
+	}
+	return nil, fmt.Errorf("Failed to find request %v: unsupported collection %v", name, collection)
+}
@@ -0,0 +1,13 @@
+package themes
+
+import (
+	"time"
+
+	"github.com/aubm/postmanerator/postman"
+)
+
+var now = time.Now
+
+func helperPastSunset(req postman.Request) bool {
+	return req.Lifecycle.PastSunset(now())
+}
@@ -0,0 +1,32 @@
+package themes
+
+import (
+	"fmt"
+	"path"
+	"path/filepath"
+)
+
+func helperRelativeURL(from *Page, to interface{}) (string, error) {
+	var target string
+	switch t := to.(type) {
+	case *Page:
+		if t == nil {
+			return "", fmt.Errorf("Failed to build relative URL: target page does not exist")
+		}
+		target = t.Path
+	case string:
+		target = t
+	default:
+		return "", fmt.Errorf("Failed to build relative URL: unsupported target %v", to)
+	}
+
+	if from == nil {
+		return target, nil
+	}
+
+	rel, err := filepath.Rel(filepath.FromSlash(path.Dir(from.Path)), filepath.FromSlash(target))
+	if err != nil {
+		return "", fmt.Errorf("Failed to build relative URL: %v", err)
+	}
+	return filepath.ToSlash(rel), nil
+}
@@ -8,8 +8,10 @@ import (
 	"net/http"
 	"os"
 	"path"
+	"path/filepath"
 	"regexp"
 	"sort"
+	"strings"
 	"time"
 
 	"github.com/aubm/postmanerator/configuration"
@@ -21,6 +23,16 @@ var (
 	gitUrlRegexp     = regexp.MustCompile(`(https?:\/\/)|(git@)`)
 )
 
+// MissingParentError tells that the theme a theme extends is not installed.
+type MissingParentError struct {
+	Theme  string
+	Parent string
+}
+
+func (e *MissingParentError) Error() string {
+	return fmt.Sprintf("Theme %v extends %v, which is not installed", e.Theme, e.Parent)
+}
+
 type Manager struct {
 	Config *configuration.Configuration `inject:""`
 	Cloner interface {
@@ -60,13 +72,71 @@ func (m *Manager) Download(theme string) (err error) {
 		}
 	}
 
-	return m.clone(theme, localName)
+	if m.Config.ThemeLocalName != "" {
+		localName = m.Config.ThemeLocalName
+	}
+
+	if err = m.clone(theme, localName); err != nil {
+		return
+	}
+
+	if localName == "" {
+		localName = m.repositoryName(theme)
+	}
+	// a clone that did not produce the theme directory is reported when the theme is opened
+	if err = m.DownloadParents(localName); err == ErrThemeNotFound {
+		return nil
+	}
+	return
+}
+
+// DownloadParents downloads the parents of an installed theme that are missing, and their own parents. Parents given as
+// a path cannot be downloaded, their *MissingParentError is returned as is, like the other errors opening the theme.
+// Parents are cloned under their own name, the custom local name of the theme does not apply to them.
+func (m *Manager) DownloadParents(themeName string) error {
+	downloaded := make(map[string]bool)
+	for {
+		_, err := m.Open(themeName)
+		missing, ok := err.(*MissingParentError)
+		if !ok {
+			return err
+		}
+		if downloaded[missing.Parent] || m.isPath(missing.Parent) {
+			return err
+		}
+		downloaded[missing.Parent] = true
+
+		url, localName := missing.Parent, ""
+		if !m.isGitUrl(url) {
+			localName = url
+			if url, err = m.getThemeURL(url); err != nil {
+				return fmt.Errorf("Failed to download parent theme %v: %v", missing.Parent, err)
+			}
+		}
+		if err := m.clone(url, localName); err != nil {
+			return fmt.Errorf("Failed to download parent theme %v: %v", missing.Parent, err)
+		}
+	}
+}
+
+// repositoryName returns the name of the directory a git repository is cloned to.
+func (m *Manager) repositoryName(url string) string {
+	url = strings.TrimSuffix(strings.TrimSuffix(url, "/"), ".git")
+	if i := strings.LastIndexAny(url, "/:"); i >= 0 {
+		return url[i+1:]
+	}
+	return url
 }
 
 func (m *Manager) isGitUrl(theme string) bool {
 	return gitUrlRegexp.MatchString(theme)
 }
 
+// isPath tells whether a parent theme is given as a path, relative to the theme directory or absolute.
+func (m *Manager) isPath(theme string) bool {
+	return strings.HasPrefix(theme, ".") || filepath.IsAbs(theme)
+}
+
 func (m *Manager) getThemeURL(themeName string) (string, error) {
 	r, err := m.getThemesListReader()
 	if err != nil {
@@ -113,10 +183,6 @@ func (m *Manager) searchForThemeUrlInThemeList(themeList io.Reader, themeNameToS
 func (m *Manager) clone(url string, localName string) error {
 	args := []string{url}
 
-	if m.Config.ThemeLocalName != "" {
-		localName = m.Config.ThemeLocalName
-	}
-
 	if localName != "" {
 		args = append(args, localName)
 	}
@@ -125,21 +191,162 @@ func (m *Manager) clone(url string, localName string) error {
 	return m.Cloner.Clone(args, options)
 }
 
+// Open reads a theme, and the themes it extends. When a parent theme is not installed, a *MissingParentError is
+// returned.
 func (m *Manager) Open(themeName string) (*Theme, error) {
+	return m.open(themeName, nil)
+}
+
+func (m *Manager) open(themeName string, children []string) (*Theme, error) {
 	themePath, err := m.getThemePath(themeName)
 	if err != nil {
 		return nil, err
 	}
 
+	for _, child := range children {
+		if filepath.Clean(child) == filepath.Clean(themePath) {
+			return nil, fmt.Errorf("Theme inheritance cycle: %v -> %v", strings.Join(children, " -> "), themePath)
+		}
+	}
+
 	theme := &Theme{Name: themeName, Path: themePath}
 	theme.Files, err = m.listThemeFiles(themePath)
 	if err != nil {
 		return nil, err
 	}
 
+	theme.Manifest, err = readManifest(themePath)
+	if err != nil {
+		return nil, err
+	}
+
+	theme.Assets, err = m.listThemeAssets(themePath)
+	if err != nil {
+		return nil, err
+	}
+
+	if theme.Manifest != nil {
+		if err := m.applyManifest(theme); err != nil {
+			return nil, err
+		}
+	}
+
+	if theme.Manifest != nil && theme.Manifest.Extends != "" {
+		theme.Parent, err = m.openParent(theme, append(children, themePath))
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if theme.Manifest != nil {
+		if err := m.checkEntry(theme); err != nil {
+			return nil, err
+		}
+	}
+
 	return theme, nil
 }
 
+// openParent opens the theme a theme extends, given as the name of an installed theme, as a git URL, or as a path
+// relative to the theme directory.
+func (m *Manager) openParent(theme *Theme, children []string) (*Theme, error) {
+	parent := theme.Manifest.Extends
+	name := parent
+	switch {
+	case m.isGitUrl(parent):
+		name = m.repositoryName(parent)
+	case m.isPath(parent) && !filepath.IsAbs(parent):
+		name = filepath.Join(theme.Path, filepath.FromSlash(parent))
+	}
+
+	parentTheme, err := m.open(name, children)
+	if err == ErrThemeNotFound {
+		return nil, &MissingParentError{Theme: theme.Name, Parent: parent}
+	}
+	return parentTheme, err
+}
+
+// applyManifest selects the templates and the assets matching the globs of the manifest.
+func (m *Manager) applyManifest(theme *Theme) error {
+	if len(theme.Manifest.Templates) > 0 {
+		files := make([]string, 0)
+		for _, pattern := range theme.Manifest.Templates {
+			matches, err := filepath.Glob(filepath.Join(theme.Path, filepath.FromSlash(pattern)))
+			if err != nil {
+				return fmt.Errorf("Invalid template pattern %v in theme manifest: %v", pattern, err)
+			}
+			for _, match := range matches {
+				if info, err := os.Stat(match); err == nil && !info.IsDir() && !containsFile(files, filepath.ToSlash(match)) {
+					files = append(files, filepath.ToSlash(match))
+				}
+			}
+		}
+		sort.Strings(files)
+		if err := checkTemplateNames(theme.Name, files); err != nil {
+			return err
+		}
+		theme.Files = files
+	}
+
+	if len(theme.Manifest.Assets) > 0 {
+		assets := make([]string, 0)
+		for _, asset := range theme.Assets {
+			for _, pattern := range theme.Manifest.Assets {
+				if matchAssetPattern(pattern, asset) {
+					assets = append(assets, asset)
+					break
+				}
+			}
+		}
+		theme.Assets = assets
+	}
+
+	return nil
+}
+
+// checkTemplateNames fails when two templates of a theme have the same file name, templates being named after their
+// file name whatever their directory.
+func checkTemplateNames(themeName string, files []string) error {
+	names := make(map[string]string)
+	for _, file := range files {
+		name := path.Base(file)
+		if other, ok := names[name]; ok {
+			return fmt.Errorf("Templates %v and %v of theme %v have the same name %v", other, file, themeName, name)
+		}
+		names[name] = file
+	}
+	return nil
+}
+
+// checkEntry fails when the entry template is neither one of the templates of the theme, nor one of its parents.
+func (m *Manager) checkEntry(theme *Theme) error {
+	entry := theme.entry()
+	for _, file := range theme.templateFiles() {
+		if path.Base(file) == entry {
+			return nil
+		}
+	}
+	return fmt.Errorf("Entry template %v not found in theme %v", entry, theme.Name)
+}
+
+// matchAssetPattern matches an asset path against a glob, a pattern ending with "/**" matching a whole directory.
+func matchAssetPattern(pattern, asset string) bool {
+	if strings.HasSuffix(pattern, "/**") {
+		return strings.HasPrefix(asset, strings.TrimSuffix(pattern, "**"))
+	}
+	ok, _ := path.Match(pattern, asset)
+	return ok
+}
+
+func containsFile(files []string, file string) bool {
+	for _, f := range files {
+		if f == file {
+			return true
+		}
+	}
+	return false
+}
+
 func (m *Manager) getThemePath(theme string) (string, error) {
 	if ok := m.directoryExists(theme); ok {
 		return theme, nil
@@ -169,7 +376,7 @@ func (m *Manager) listThemeFiles(themePath string) ([]string, error) {
 
 	themeFiles := make([]string, 0)
 	for _, entry := range contents {
-		if !entry.IsDir() {
+		if !entry.IsDir() && !isManifestFile(entry.Name()) {
 			file := path.Join(themePath, entry.Name())
 			themeFiles = append(themeFiles, file)
 		}
@@ -180,6 +387,33 @@ func (m *Manager) listThemeFiles(themePath string) ([]string, error) {
 	return themeFiles, nil
 }
 
+func (m *Manager) listThemeAssets(themePath string) ([]string, error) {
+	assetsPath := filepath.Join(themePath, assetsDirectory)
+	if !m.directoryExists(assetsPath) {
+		return nil, nil
+	}
+
+	assets := make([]string, 0)
+	err := filepath.Walk(assetsPath, func(file string, info os.FileInfo, err error) error {
+		if err != nil || info.IsDir() {
+			return err
+		}
+		asset, err := filepath.Rel(assetsPath, file)
+		if err != nil {
+			return err
+		}
+		assets = append(assets, filepath.ToSlash(asset))
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("Failed to read theme assets: %v", err)
+	}
+
+	sort.Strings(assets)
+
+	return assets, nil
+}
+
 func (m *Manager) readDir(directory string) ([]os.FileInfo, error) {
 	dirToRead, err := os.Open(directory)
 	if err != nil {
@@ -2,6 +2,8 @@ package themes_test
 
 import (
 	"errors"
+	"fmt"
+	"io/ioutil"
 	"net/http"
 	"net/http/httptest"
 	"os"
@@ -235,6 +237,54 @@ var _ = Describe("Manager", func() {
 
 		})
 
+		Context("when the theme extends a theme that is not installed", func() {
+
+			BeforeEach(func() {
+				themeToDownload = "https://github.com/acme/corporate-theme.git"
+				mockCloner.On("Clone", any, any).Return(nil).Run(func(args mock.Arguments) {
+					switch args.Get(0).([]string)[0] {
+					case "https://github.com/acme/corporate-theme.git":
+						must(os.Mkdir(path.Join(createdTmpThemesDirectory, "corporate-theme"), 0777))
+						must(ioutil.WriteFile(path.Join(createdTmpThemesDirectory, "corporate-theme", "theme.yaml"), []byte("extends: default\n"), 0666))
+					case "https://github.com/aubm/postmanerator-default-theme.git":
+						must(os.Mkdir(path.Join(createdTmpThemesDirectory, "default"), 0777))
+						must(ioutil.WriteFile(path.Join(createdTmpThemesDirectory, "default", "index.tpl"), nil, 0666))
+					}
+				})
+			})
+
+			It("should not return an error", func() {
+				Expect(returnedError).To(BeNil())
+			})
+
+			It("should download the parent theme", func() {
+				Expect(len(mockCloner.Calls)).To(Equal(2))
+				Expect(mockCloner.Calls[1].Arguments.Get(0)).To(Equal([]string{"https://github.com/aubm/postmanerator-default-theme.git", "default"}))
+			})
+
+		})
+
+		Context("when the theme extends a theme given as a path that is not installed", func() {
+
+			BeforeEach(func() {
+				themeToDownload = "https://github.com/acme/corporate-theme.git"
+				mockCloner.On("Clone", any, any).Return(nil).Run(func(args mock.Arguments) {
+					must(os.Mkdir(path.Join(createdTmpThemesDirectory, "corporate-theme"), 0777))
+					must(ioutil.WriteFile(path.Join(createdTmpThemesDirectory, "corporate-theme", "theme.yaml"), []byte("extends: ./base\n"), 0666))
+				})
+			})
+
+			It("should return a missing parent error", func() {
+				Expect(returnedError).To(Equal(&MissingParentError{Theme: "corporate-theme", Parent: "./base"}))
+			})
+
+			It("should not look for the parent theme in the themes repository", func() {
+				Expect(len(mockCloner.Calls)).To(Equal(1))
+				Expect(themesRepositoryGeneratedRequests).To(BeEmpty())
+			})
+
+		})
+
 		Context("when the clone fails", func() {
 
 			BeforeEach(func() {
@@ -361,6 +411,52 @@ var _ = Describe("Manager", func() {
 
 	})
 
+	Describe("DownloadParents", func() {
+
+		var returnedError error
+
+		BeforeEach(func() {
+			manager.Config.ThemeLocalName = "my-custom-name"
+			must(os.Mkdir(path.Join(createdTmpThemesDirectory, "child"), 0777))
+			must(ioutil.WriteFile(path.Join(createdTmpThemesDirectory, "child", "theme.yaml"), []byte("extends: default\n"), 0666))
+			mockCloner.On("Clone", any, any).Return(nil).Run(func(args mock.Arguments) {
+				must(os.Mkdir(path.Join(createdTmpThemesDirectory, "default"), 0777))
+				must(ioutil.WriteFile(path.Join(createdTmpThemesDirectory, "default", "index.tpl"), nil, 0666))
+			})
+		})
+
+		JustBeforeEach(func() {
+			returnedError = manager.DownloadParents("child")
+		})
+
+		It("should not return an error", func() {
+			Expect(returnedError).To(BeNil())
+		})
+
+		It("should clone the parent theme under its own name", func() {
+			Expect(len(mockCloner.Calls)).To(Equal(1))
+			Expect(mockCloner.Calls[0].Arguments.Get(0)).To(Equal([]string{"https://github.com/aubm/postmanerator-default-theme.git", "default"}))
+		})
+
+		Context("when the downloaded parent theme cannot be opened", func() {
+
+			BeforeEach(func() {
+				mockCloner.ExpectedCalls = nil
+				mockCloner.On("Clone", any, any).Return(nil).Run(func(args mock.Arguments) {
+					must(os.Mkdir(path.Join(createdTmpThemesDirectory, "default"), 0777))
+					must(ioutil.WriteFile(path.Join(createdTmpThemesDirectory, "default", "theme.yaml"), []byte("unknown: true\n"), 0666))
+				})
+			})
+
+			It("should return an error", func() {
+				Expect(returnedError).NotTo(BeNil())
+				Expect(returnedError).NotTo(BeAssignableToTypeOf(&MissingParentError{}))
+			})
+
+		})
+
+	})
+
 	Describe("Open", func() {
 
 		var (
@@ -384,6 +480,9 @@ var _ = Describe("Manager", func() {
 			must(os.Create(path.Join(createdTmpThemesDirectory, "default", "index.tpl")))
 			must(os.Create(path.Join(createdTmpThemesDirectory, "default", "menu.tpl")))
 			must(os.Create(path.Join(createdTmpThemesDirectory, "default", "theme.css")))
+			must(os.MkdirAll(path.Join(createdTmpThemesDirectory, "default", "assets", "img"), 0777))
+			must(os.Create(path.Join(createdTmpThemesDirectory, "default", "assets", "style.css")))
+			must(os.Create(path.Join(createdTmpThemesDirectory, "default", "assets", "img", "logo.png")))
 			must(os.Create(path.Join(createdTmpThemesDirectory, "invalid-theme")))
 		})
 
@@ -408,6 +507,7 @@ var _ = Describe("Manager", func() {
 							path.Join(createdTmpThemesDirectory, "default", "menu.tpl"),
 							path.Join(createdTmpThemesDirectory, "default", "theme.css"),
 						},
+						Assets: []string{"img/logo.png", "style.css"},
 					}))
 				})
 
@@ -428,6 +528,7 @@ var _ = Describe("Manager", func() {
 							path.Join(createdTmpThemesDirectory, "default", "menu.tpl"),
 							path.Join(createdTmpThemesDirectory, "default", "theme.css"),
 						},
+						Assets: []string{"img/logo.png", "style.css"},
 					}))
 				})
 
@@ -435,6 +536,193 @@ var _ = Describe("Manager", func() {
 
 		})
 
+		Context("when the theme has a manifest", func() {
+
+			BeforeEach(func() {
+				themeToOpen = "default"
+				must(ioutil.WriteFile(path.Join(createdTmpThemesDirectory, "default", "theme.yaml"), []byte(`name: Default
+author: Postmanerator
+version: 1.2.0
+postmanerator: ">=0.9"
+entry: menu.tpl
+templates: ["*.tpl"]
+assets: [img/**]
+helpers: [markdown]
+`), 0666))
+			})
+
+			It("should read the manifest and select the matching templates and assets", func() {
+				Expect(returnedError).To(BeNil())
+				Expect(returnedTheme).To(Equal(&Theme{
+					Name: "default",
+					Path: path.Join(createdTmpThemesDirectory, "default"),
+					Files: []string{
+						path.Join(createdTmpThemesDirectory, "default", "index.tpl"),
+						path.Join(createdTmpThemesDirectory, "default", "menu.tpl"),
+					},
+					Assets: []string{"img/logo.png"},
+					Manifest: &Manifest{
+						Name:          "Default",
+						Author:        "Postmanerator",
+						Version:       "1.2.0",
+						Postmanerator: ">=0.9",
+						Entry:         "menu.tpl",
+						Templates:     []string{"*.tpl"},
+						Assets:        []string{"img/**"},
+						Helpers:       []string{"markdown"},
+					},
+				}))
+			})
+
+			Context("in the JSON format", func() {
+
+				BeforeEach(func() {
+					must(os.Remove(path.Join(createdTmpThemesDirectory, "default", "theme.yaml")))
+					must(ioutil.WriteFile(path.Join(createdTmpThemesDirectory, "default", "theme.json"), []byte(`{"name": "Default"}`), 0666))
+				})
+
+				It("should read the manifest and keep all the files but the manifest", func() {
+					Expect(returnedError).To(BeNil())
+					Expect(returnedTheme.Manifest).To(Equal(&Manifest{Name: "Default"}))
+					Expect(returnedTheme.Files).To(Equal([]string{
+						path.Join(createdTmpThemesDirectory, "default", "index.tpl"),
+						path.Join(createdTmpThemesDirectory, "default", "menu.tpl"),
+						path.Join(createdTmpThemesDirectory, "default", "theme.css"),
+					}))
+				})
+
+			})
+
+			Context("in the JSON format with an unknown key", func() {
+
+				BeforeEach(func() {
+					must(os.Remove(path.Join(createdTmpThemesDirectory, "default", "theme.yaml")))
+					must(ioutil.WriteFile(path.Join(createdTmpThemesDirectory, "default", "theme.json"), []byte(`{"entri": "menu.tpl"}`), 0666))
+				})
+
+				It("should return an error", func() {
+					Expect(returnedError).NotTo(BeNil())
+					Expect(returnedError.Error()).To(ContainSubstring(`Failed to parse theme manifest theme.json: json: unknown field "entri"`))
+				})
+
+			})
+
+			Context("and two templates have the same file name", func() {
+
+				BeforeEach(func() {
+					must(os.Mkdir(path.Join(createdTmpThemesDirectory, "default", "partials"), 0777))
+					must(ioutil.WriteFile(path.Join(createdTmpThemesDirectory, "default", "partials", "menu.tpl"), nil, 0666))
+					must(ioutil.WriteFile(path.Join(createdTmpThemesDirectory, "default", "theme.yaml"), []byte("templates: [\"*.tpl\", \"partials/*.tpl\"]\n"), 0666))
+				})
+
+				It("should return an error", func() {
+					Expect(returnedError).NotTo(BeNil())
+					Expect(returnedError.Error()).To(Equal(fmt.Sprintf("Templates %v and %v of theme default have the same name menu.tpl",
+						path.Join(createdTmpThemesDirectory, "default", "menu.tpl"),
+						path.Join(createdTmpThemesDirectory, "default", "partials", "menu.tpl"))))
+				})
+
+			})
+
+			Context("and the entry template does not exist", func() {
+
+				BeforeEach(func() {
+					must(ioutil.WriteFile(path.Join(createdTmpThemesDirectory, "default", "theme.yaml"), []byte("entry: home.tpl\n"), 0666))
+				})
+
+				It("should return an error", func() {
+					Expect(returnedError).NotTo(BeNil())
+					Expect(returnedError.Error()).To(Equal("Entry template home.tpl not found in theme default"))
+				})
+
+			})
+
+			Context("and an option has an invalid default value", func() {
+
+				BeforeEach(func() {
+					must(ioutil.WriteFile(path.Join(createdTmpThemesDirectory, "default", "theme.yaml"), []byte("options:\n  columns:\n    type: int\n    default: two\n"), 0666))
+				})
+
+				It("should return an error", func() {
+					Expect(returnedError).NotTo(BeNil())
+					Expect(returnedError.Error()).To(Equal(`Invalid theme option columns in theme manifest: expected an integer, got "two"`))
+				})
+
+			})
+
+			Context("and the theme extends another theme", func() {
+
+				BeforeEach(func() {
+					themeToOpen = "child"
+					must(os.Mkdir(path.Join(createdTmpThemesDirectory, "child"), 0777))
+					must(ioutil.WriteFile(path.Join(createdTmpThemesDirectory, "child", "menu.tpl"), nil, 0666))
+					must(ioutil.WriteFile(path.Join(createdTmpThemesDirectory, "child", "theme.yaml"), []byte("extends: default\n"), 0666))
+				})
+
+				It("should open the parent theme", func() {
+					Expect(returnedError).To(BeNil())
+					Expect(returnedTheme.Files).To(Equal([]string{path.Join(createdTmpThemesDirectory, "child", "menu.tpl")}))
+					Expect(returnedTheme.Parent).NotTo(BeNil())
+					Expect(returnedTheme.Parent.Name).To(Equal("default"))
+					Expect(returnedTheme.Parent.Manifest.Entry).To(Equal("menu.tpl"))
+				})
+
+				Context("given as a relative path", func() {
+
+					BeforeEach(func() {
+						must(ioutil.WriteFile(path.Join(createdTmpThemesDirectory, "child", "theme.yaml"), []byte("extends: ../default\n"), 0666))
+					})
+
+					It("should open the parent theme", func() {
+						Expect(returnedError).To(BeNil())
+						Expect(returnedTheme.Parent.Path).To(Equal(path.Join(createdTmpThemesDirectory, "default")))
+					})
+
+				})
+
+				Context("that is not installed", func() {
+
+					BeforeEach(func() {
+						must(ioutil.WriteFile(path.Join(createdTmpThemesDirectory, "child", "theme.yaml"), []byte("extends: markdown\n"), 0666))
+					})
+
+					It("should return a missing parent error", func() {
+						Expect(returnedError).To(Equal(&MissingParentError{Theme: "child", Parent: "markdown"}))
+						Expect(returnedError.Error()).To(Equal("Theme child extends markdown, which is not installed"))
+					})
+
+				})
+
+				Context("that extends it", func() {
+
+					BeforeEach(func() {
+						must(ioutil.WriteFile(path.Join(createdTmpThemesDirectory, "default", "theme.yaml"), []byte("extends: child\n"), 0666))
+					})
+
+					It("should return an error", func() {
+						Expect(returnedError).NotTo(BeNil())
+						Expect(returnedError.Error()).To(HavePrefix("Theme inheritance cycle:"))
+					})
+
+				})
+
+			})
+
+			Context("and the manifest is invalid", func() {
+
+				BeforeEach(func() {
+					must(ioutil.WriteFile(path.Join(createdTmpThemesDirectory, "default", "theme.yaml"), []byte("colour: blue\n"), 0666))
+				})
+
+				It("should return an error", func() {
+					Expect(returnedError).NotTo(BeNil())
+					Expect(returnedError.Error()).To(ContainSubstring("Failed to parse theme manifest theme.yaml:"))
+				})
+
+			})
+
+		})
+
 		Context("when the theme does not exist", func() {
 
 			BeforeEach(func() {
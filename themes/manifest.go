@@ -0,0 +1,126 @@
+package themes
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/aubm/postmanerator/configuration"
+	yaml "gopkg.in/yaml.v2"
+)
+
+var manifestFiles = []string{"theme.json", "theme.yaml", "theme.yml"}
+
+// Manifest describes a theme, it is read from the theme.json or the theme.yaml file of the theme directory.
+type Manifest struct {
+	Name        string `json:"name" yaml:"name"`
+	Description string `json:"description" yaml:"description"`
+	Author      string `json:"author" yaml:"author"`
+	Version     string `json:"version" yaml:"version"`
+	// Extends is the theme this theme is based on, as the name of a theme, a git URL or a path relative to the theme
+	// directory. Its templates are parsed first, so that the ones of this theme override them.
+	Extends string `json:"extends" yaml:"extends"`
+	// Postmanerator is the version constraint Postmanerator must satisfy, such as ">=1.2.0 <2.0.0" or "^1.2".
+	Postmanerator string `json:"postmanerator" yaml:"postmanerator"`
+	// Entry is the template rendered in single page mode, and as the index page in multi-page mode, default is index.tpl.
+	Entry string `json:"entry" yaml:"entry"`
+	// Templates are globs relative to the theme directory, default is all the files of the theme directory.
+	Templates []string `json:"templates" yaml:"templates"`
+	// Assets are globs relative to the assets directory, default is all the files of the assets directory.
+	Assets []string `json:"assets" yaml:"assets"`
+	// Helpers are the template helpers the theme uses, Postmanerator must provide all of them.
+	Helpers []string `json:"helpers" yaml:"helpers"`
+	// Options are the settings of the theme, available to the templates as .Options.
+	Options map[string]OptionDefinition `json:"options" yaml:"options"`
+}
+
+// readManifest reads the manifest of a theme, it returns nil when the theme has none.
+func readManifest(themePath string) (*Manifest, error) {
+	for _, name := range manifestFiles {
+		contents, err := ioutil.ReadFile(filepath.Join(themePath, name))
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("Failed to read theme manifest: %v", err)
+		}
+		manifest := &Manifest{}
+		if path.Ext(name) == ".json" {
+			decoder := json.NewDecoder(bytes.NewReader(contents))
+			decoder.DisallowUnknownFields()
+			err = decoder.Decode(manifest)
+		} else {
+			err = yaml.UnmarshalStrict(contents, manifest)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("Failed to parse theme manifest %v: %v", name, err)
+		}
+		return manifest, manifest.checkOptions()
+	}
+	return nil, nil
+}
+
+func isManifestFile(file string) bool {
+	for _, name := range manifestFiles {
+		if path.Base(file) == name {
+			return true
+		}
+	}
+	return false
+}
+
+// entry returns the name of the template rendered first.
+func (t *Theme) entry() string {
+	if t.Manifest != nil && t.Manifest.Entry != "" {
+		return path.Base(t.Manifest.Entry)
+	}
+	if t.Parent != nil {
+		return t.Parent.entry()
+	}
+	return mainThemeFile
+}
+
+// pageTemplate returns the name of the template rendering a kind of page in multi-page mode.
+func (t *Theme) pageTemplate(kind string) string {
+	if kind == PageIndex {
+		return t.entry()
+	}
+	return pageThemeFiles[kind]
+}
+
+// checkRequirements fails when the running version of Postmanerator, or its helpers, do not meet the requirements
+// of the manifests of the theme and of its parents. The version constraints are not checked for development builds.
+func (t *Theme) checkRequirements(version string, helpers map[string]interface{}) error {
+	if t.Parent != nil {
+		if err := t.Parent.checkRequirements(version, helpers); err != nil {
+			return err
+		}
+	}
+	if t.Manifest == nil {
+		return nil
+	}
+	if constraint := t.Manifest.Postmanerator; constraint != "" && version != configuration.DevVersion {
+		ok, err := satisfiesConstraint(version, constraint)
+		if err != nil {
+			return fmt.Errorf("Invalid postmanerator constraint in theme %v: %v", t.Name, err)
+		}
+		if !ok {
+			return fmt.Errorf("Theme %v requires postmanerator %v, current version is %v", t.Name, constraint, version)
+		}
+	}
+	missing := make([]string, 0)
+	for _, helper := range t.Manifest.Helpers {
+		if _, ok := helpers[helper]; !ok {
+			missing = append(missing, helper)
+		}
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("Theme %v requires helpers that are not available: %v", t.Name, strings.Join(missing, ", "))
+	}
+	return nil
+}
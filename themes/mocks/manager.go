@@ -18,6 +18,10 @@ func (m *MockThemeManager) Download(themeName string) error {
 	return m.Called(themeName).Error(0)
 }
 
+func (m *MockThemeManager) DownloadParents(themeName string) error {
+	return m.Called(themeName).Error(0)
+}
+
 func (m *MockThemeManager) Delete(theme string) error {
 	return m.Called(theme).Error(0)
 }
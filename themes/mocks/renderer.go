@@ -15,3 +15,7 @@ type MockThemeRenderer struct {
 func (m *MockThemeRenderer) Render(w io.Writer, theme *Theme, collection postman.Collection) error {
 	return m.Called(w, theme, collection).Error(0)
 }
+
+func (m *MockThemeRenderer) RenderPages(out Output, theme *Theme, collection postman.Collection) error {
+	return m.Called(out, theme, collection).Error(0)
+}
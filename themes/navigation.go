@@ -0,0 +1,147 @@
+package themes
+
+import (
+	"fmt"
+	"path"
+
+	"github.com/aubm/postmanerator/postman"
+)
+
+const (
+	PageIndex   = "index"
+	PageFolder  = "folder"
+	PageRequest = "request"
+)
+
+// Page is a single file generated in multi-page mode.
+type Page struct {
+	Kind     string
+	Path     string
+	Title    string
+	Folder   *postman.Folder
+	Request  *postman.Request
+	Parent   *Page
+	Children []*Page
+}
+
+// Breadcrumbs returns the ancestors of the page, starting from the index page.
+func (p *Page) Breadcrumbs() []*Page {
+	breadcrumbs := make([]*Page, 0)
+	for parent := p.Parent; parent != nil; parent = parent.Parent {
+		breadcrumbs = append([]*Page{parent}, breadcrumbs...)
+	}
+	return breadcrumbs
+}
+
+// Navigation is the tree of the pages generated in multi-page mode, shared by all pages.
+type Navigation struct {
+	Root     *Page
+	Pages    []*Page
+	folders  map[string]*Page
+	requests map[string]*Page
+}
+
+// FolderPage returns the page generated for the folder with the given ID, or nil.
+func (n *Navigation) FolderPage(id string) *Page {
+	return n.folders[id]
+}
+
+// RequestPage returns the page generated for the request with the given ID, or nil.
+func (n *Navigation) RequestPage(id string) *Page {
+	return n.requests[id]
+}
+
+// PageData is what templates receive, the collection fields remain available at the top level. Page and Navigation
+// are nil in single page mode.
+type PageData struct {
+	postman.Collection
+	Page       *Page
+	Navigation *Navigation
+	// Options are the values of the options declared by the theme manifest.
+	Options map[string]interface{}
+}
+
+type navigationBuilder struct {
+	navigation    *Navigation
+	usedPaths     map[string]bool
+	folderPages   bool
+	requestPages  bool
+	pageExtension string
+}
+
+func newNavigation(collection postman.Collection, folderPages, requestPages bool) *Navigation {
+	b := &navigationBuilder{
+		navigation: &Navigation{
+			Pages:    make([]*Page, 0),
+			folders:  make(map[string]*Page),
+			requests: make(map[string]*Page),
+		},
+		usedPaths:     make(map[string]bool),
+		folderPages:   folderPages,
+		requestPages:  requestPages,
+		pageExtension: pageExtension,
+	}
+
+	root := b.addPage(nil, &Page{Kind: PageIndex, Path: b.uniquePath("", "index", b.pageExtension), Title: collection.Name})
+	b.navigation.Root = root
+	b.addFolderContents(root, "", collection.Folders, collection.Requests)
+
+	return b.navigation
+}
+
+func (b *navigationBuilder) addFolderContents(parent *Page, dir string, folders []postman.Folder, requests []postman.Request) {
+	for i := range requests {
+		request := &requests[i]
+		if !b.requestPages {
+			continue
+		}
+		page := b.addPage(parent, &Page{
+			Kind:    PageRequest,
+			Path:    b.uniquePath(dir, helperSlugify(request.Name), b.pageExtension),
+			Title:   request.Name,
+			Request: request,
+		})
+		b.navigation.requests[request.ID] = page
+	}
+
+	for i := range folders {
+		folder := &folders[i]
+		folderDir := b.uniquePath(dir, helperSlugify(folder.Name), "/")
+		folderParent := parent
+		if b.folderPages {
+			folderParent = b.addPage(parent, &Page{
+				Kind:   PageFolder,
+				Path:   b.uniquePath(folderDir, "index", b.pageExtension),
+				Title:  folder.Name,
+				Folder: folder,
+			})
+			b.navigation.folders[folder.ID] = folderParent
+		}
+		b.addFolderContents(folderParent, folderDir, folder.Folders, folder.Requests)
+	}
+}
+
+func (b *navigationBuilder) addPage(parent *Page, page *Page) *Page {
+	page.Parent = parent
+	page.Children = make([]*Page, 0)
+	if parent != nil {
+		parent.Children = append(parent.Children, page)
+	}
+	b.navigation.Pages = append(b.navigation.Pages, page)
+	return page
+}
+
+func (b *navigationBuilder) uniquePath(dir, slug, suffix string) string {
+	if slug == "" {
+		slug = "page"
+	}
+	candidate := path.Join(dir, slug)
+	for i := 2; b.usedPaths[candidate+suffix]; i++ {
+		candidate = path.Join(dir, fmt.Sprintf("%s-%d", slug, i))
+	}
+	b.usedPaths[candidate+suffix] = true
+	if suffix == "/" {
+		return candidate
+	}
+	return candidate + suffix
+}
@@ -0,0 +1,131 @@
+package themes
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Types of the theme options.
+const (
+	OptionString = "string"
+	OptionBool   = "bool"
+	OptionInt    = "int"
+	OptionNumber = "number"
+)
+
+// OptionDefinition describes an option of a theme, whose value is given with -theme-option key=value.
+type OptionDefinition struct {
+	// Type is OptionString, the default, OptionBool, OptionInt or OptionNumber.
+	Type        string      `json:"type" yaml:"type"`
+	Default     interface{} `json:"default" yaml:"default"`
+	Description string      `json:"description" yaml:"description"`
+	// Values restricts the values of a string option.
+	Values []string `json:"values" yaml:"values"`
+}
+
+// parse converts the value of an option to its type.
+func (d OptionDefinition) parse(value string) (interface{}, error) {
+	switch d.Type {
+	case "", OptionString:
+		if len(d.Values) > 0 && !containsString(d.Values, value) {
+			return nil, fmt.Errorf("expected one of %v, got %q", strings.Join(d.Values, ", "), value)
+		}
+		return value, nil
+	case OptionBool:
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return nil, fmt.Errorf("expected a boolean, got %q", value)
+		}
+		return b, nil
+	case OptionInt:
+		i, err := strconv.Atoi(value)
+		if err != nil {
+			return nil, fmt.Errorf("expected an integer, got %q", value)
+		}
+		return i, nil
+	case OptionNumber:
+		f, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return nil, fmt.Errorf("expected a number, got %q", value)
+		}
+		return f, nil
+	}
+	return nil, fmt.Errorf("unknown type %v, expected %v, %v, %v or %v", d.Type, OptionString, OptionBool, OptionInt, OptionNumber)
+}
+
+// defaultValue returns the default value of an option, or the first of its values, or the zero value of its type.
+func (d OptionDefinition) defaultValue() (interface{}, error) {
+	if d.Default == nil {
+		if len(d.Values) > 0 {
+			return d.parse(d.Values[0])
+		}
+		switch d.Type {
+		case OptionBool:
+			return false, nil
+		case OptionInt:
+			return 0, nil
+		case OptionNumber:
+			return 0.0, nil
+		}
+		return d.parse("")
+	}
+	return d.parse(fmt.Sprint(d.Default))
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// checkOptions fails when an option of the manifest has an unknown type or an invalid default value.
+func (m *Manifest) checkOptions() error {
+	names := make([]string, 0, len(m.Options))
+	for name := range m.Options {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if _, err := m.Options[name].defaultValue(); err != nil {
+			return fmt.Errorf("Invalid theme option %v in theme manifest: %v", name, err)
+		}
+	}
+	return nil
+}
+
+// options returns the values of the options of the theme and of its parents, given as "key=value" strings, or their default values.
+func (t *Theme) options(values []string) (map[string]interface{}, error) {
+	definitions := t.optionDefinitions()
+
+	options := make(map[string]interface{})
+	for name, definition := range definitions {
+		value, err := definition.defaultValue()
+		if err != nil {
+			return nil, fmt.Errorf("Invalid theme option %v in theme manifest: %v", name, err)
+		}
+		options[name] = value
+	}
+
+	for _, option := range values {
+		parts := strings.SplitN(option, "=", 2)
+		if len(parts) != 2 || strings.TrimSpace(parts[0]) == "" {
+			return nil, fmt.Errorf("Invalid theme option %v, expected key=value", option)
+		}
+		name := strings.TrimSpace(parts[0])
+		definition, ok := definitions[name]
+		if !ok {
+			return nil, fmt.Errorf("Unknown option %v for theme %v", name, t.Name)
+		}
+		value, err := definition.parse(parts[1])
+		if err != nil {
+			return nil, fmt.Errorf("Invalid value for theme option %v: %v", name, err)
+		}
+		options[name] = value
+	}
+	return options, nil
+}
@@ -0,0 +1,63 @@
+package themes
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// Output creates the files generated by the renderer in multi-page mode.
+// Names are slash separated paths, relative to the root of the output.
+type Output interface {
+	Create(name string) (io.WriteCloser, error)
+}
+
+// DirectoryOutput writes generated files under a directory of the file system.
+type DirectoryOutput struct {
+	Path string
+}
+
+func (o DirectoryOutput) Create(name string) (io.WriteCloser, error) {
+	file := filepath.Join(o.Path, filepath.FromSlash(name))
+	if err := os.MkdirAll(filepath.Dir(file), 0777); err != nil {
+		return nil, err
+	}
+	return os.Create(file)
+}
+
+// MemoryOutput keeps generated files in memory, a file is available once it has been closed.
+type MemoryOutput struct {
+	mutex sync.RWMutex
+	files map[string][]byte
+}
+
+func NewMemoryOutput() *MemoryOutput {
+	return &MemoryOutput{files: make(map[string][]byte)}
+}
+
+func (o *MemoryOutput) Create(name string) (io.WriteCloser, error) {
+	return &memoryFile{name: name, output: o}, nil
+}
+
+// File returns the contents of the generated file with the given name.
+func (o *MemoryOutput) File(name string) ([]byte, bool) {
+	o.mutex.RLock()
+	defer o.mutex.RUnlock()
+	contents, ok := o.files[name]
+	return contents, ok
+}
+
+type memoryFile struct {
+	bytes.Buffer
+	name   string
+	output *MemoryOutput
+}
+
+func (f *memoryFile) Close() error {
+	f.output.mutex.Lock()
+	defer f.output.mutex.Unlock()
+	f.output.files[f.name] = f.Bytes()
+	return nil
+}
@@ -1,36 +1,130 @@
 package themes
 
 import (
+	"fmt"
 	"io"
 	"text/template"
 
+	"github.com/aubm/postmanerator/configuration"
 	"github.com/aubm/postmanerator/postman"
 )
 
 const (
-	templateName  = ""
-	mainThemeFile = "index.tpl"
+	templateName     = ""
+	mainThemeFile    = "index.tpl"
+	folderThemeFile  = "folder.tpl"
+	requestThemeFile = "request.tpl"
+	pageExtension    = ".html"
 )
 
-type Renderer struct{}
+var pageThemeFiles = map[string]string{
+	PageIndex:   mainThemeFile,
+	PageFolder:  folderThemeFile,
+	PageRequest: requestThemeFile,
+}
+
+type Renderer struct {
+	Config *configuration.Configuration `inject:""`
+}
+
+// config returns the injected configuration, or the default one for a Renderer built without it.
+func (r *Renderer) config() *configuration.Configuration {
+	if r.Config == nil {
+		return &configuration.Configuration{}
+	}
+	return r.Config
+}
 
 func (r *Renderer) Render(w io.Writer, theme *Theme, collection postman.Collection) error {
-	tmpl, err := template.New(templateName).Funcs(r.getTemplateHelpers()).ParseFiles(theme.Files...)
+	if err := theme.checkRequirements(configuration.Version, r.getTemplateHelpers(nil, nil)); err != nil {
+		return err
+	}
+
+	options, err := theme.options(r.config().ThemeOptions.Values)
+	if err != nil {
+		return err
+	}
+
+	tmpl, err := r.parseTheme(theme, unfingerprintedAssets(theme), bodyPaths{})
+	if err != nil {
+		return err
+	}
+	return tmpl.ExecuteTemplate(w, theme.entry(), PageData{Collection: collection, Options: options})
+}
+
+func (r *Renderer) RenderPages(out Output, theme *Theme, collection postman.Collection) error {
+	if err := theme.checkRequirements(configuration.Version, r.getTemplateHelpers(nil, nil)); err != nil {
+		return err
+	}
+
+	options, err := theme.options(r.config().ThemeOptions.Values)
+	if err != nil {
+		return err
+	}
+
+	assets, err := r.copyAssets(out, theme)
+	if err != nil {
+		return err
+	}
+
+	bodies, err := r.writeBodyFiles(out, collection)
+	if err != nil {
+		return err
+	}
+
+	tmpl, err := r.parseTheme(theme, assets, bodies)
 	if err != nil {
 		return err
 	}
-	return tmpl.ExecuteTemplate(w, mainThemeFile, collection)
+
+	navigation := newNavigation(collection,
+		tmpl.Lookup(folderThemeFile) != nil,
+		tmpl.Lookup(requestThemeFile) != nil,
+	)
+
+	for _, page := range navigation.Pages {
+		data := PageData{Collection: collection, Page: page, Navigation: navigation, Options: options}
+		if err := r.renderPage(out, tmpl, theme.pageTemplate(page.Kind), page, data); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (r *Renderer) renderPage(out Output, tmpl *template.Template, name string, page *Page, data PageData) (err error) {
+	w, err := out.Create(page.Path)
+	if err != nil {
+		return fmt.Errorf("Failed to create page %v: %v", page.Path, err)
+	}
+	defer func() {
+		if closeErr := w.Close(); err == nil {
+			err = closeErr
+		}
+	}()
+
+	return tmpl.ExecuteTemplate(w, name, data)
+}
+
+func (r *Renderer) parseTheme(theme *Theme, assets assetPaths, bodies bodyPaths) (*template.Template, error) {
+	return template.New(templateName).Funcs(r.getTemplateHelpers(assets, bodies)).ParseFiles(theme.templateFiles()...)
 }
 
-func (r *Renderer) getTemplateHelpers() template.FuncMap {
+func (r *Renderer) getTemplateHelpers(assets assetPaths, bodies bodyPaths) template.FuncMap {
 	return template.FuncMap{
+		"asset":        helperAsset(assets),
+		"bodyFile":     helperBodyFile(bodies),
 		"curlSnippet":  curlSnippet,
+		"dataURI":      helperDataURI,
+		"findRequest":  helperFindRequest,
 		"findResponse": helperFindResponse,
 		"hasContent":   helperHasContent,
 		"httpSnippet":  helperHttpSnippet,
 		"indentJSON":   helperIndentJSON,
 		"inline":       helperInline,
 		"markdown":     helperMarkdown,
+		"pastSunset":   helperPastSunset,
+		"relativeURL":  helperRelativeURL,
 		"slugify":      helperSlugify,
 	}
 }
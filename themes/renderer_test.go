@@ -2,11 +2,15 @@ package themes_test
 
 import (
 	"bytes"
+	"os"
+	"path"
 
+	"github.com/aubm/postmanerator/configuration"
 	"github.com/aubm/postmanerator/postman"
 	. "github.com/aubm/postmanerator/themes"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
+	uuid "github.com/satori/go.uuid"
 )
 
 var _ = Describe("Renderer", func() {
@@ -66,6 +70,460 @@ var _ = Describe("Renderer", func() {
 			expectedOutput = readFileContent("tests_data/themes/indent_json.out")
 		})
 
+		It("should embed the binary bodies as data URIs", func() {
+			collection = bodiesCollection
+			usedTheme = &Theme{Files: []string{"tests_data/themes/bodies/index.tpl"}}
+			expectedOutput = `Cats: [{"name":"Felix"},"… 1 more item(s)"]
+Photo: iVBORw0KGgo= <img src="data:image/png;base64,iVBORw0KGgo=">
+
+`
+		})
+
+		It("should render the lifecycle of the requests", func() {
+			collection = lifecycleCollection
+			usedTheme = &Theme{Files: []string{"tests_data/themes/lifecycle/index.tpl"}}
+			expectedOutput = readFileContent("tests_data/themes/lifecycle.out")
+		})
+
+	})
+
+	Describe("Render with a theme manifest", func() {
+
+		var (
+			outputWriter *bytes.Buffer
+			usedTheme    *Theme
+		)
+
+		BeforeEach(func() {
+			outputWriter = new(bytes.Buffer)
+			usedTheme = &Theme{
+				Name:     "hard_coded",
+				Files:    []string{"tests_data/themes/hard_coded/index.tpl", "tests_data/themes/simple/index.tpl"},
+				Manifest: &Manifest{},
+			}
+		})
+
+		It("should render the entry template", func() {
+			usedTheme.Files = append(usedTheme.Files, "tests_data/themes/manifest/home.tpl")
+			usedTheme.Manifest.Entry = "home.tpl"
+			Expect(renderer.Render(outputWriter, usedTheme, exampleCollection)).To(BeNil())
+			Expect(outputWriter.String()).To(Equal("HOME My Collection\n"))
+		})
+
+		It("should fail when the entry template does not exist", func() {
+			usedTheme.Manifest.Entry = "home.tpl"
+			err := renderer.Render(outputWriter, usedTheme, exampleCollection)
+			Expect(err).NotTo(BeNil())
+			Expect(err.Error()).To(ContainSubstring(`no template "home.tpl"`))
+		})
+
+		Context("when Postmanerator is a release", func() {
+
+			var version string
+
+			BeforeEach(func() {
+				version = configuration.Version
+				configuration.Version = "1.4.2"
+			})
+
+			AfterEach(func() {
+				configuration.Version = version
+			})
+
+			It("should accept a satisfied version constraint", func() {
+				usedTheme.Manifest.Postmanerator = ">=0.1.0, <99 ^1.4"
+				Expect(renderer.Render(outputWriter, usedTheme, exampleCollection)).To(BeNil())
+			})
+
+			It("should fail when the version constraint is not satisfied", func() {
+				usedTheme.Manifest.Postmanerator = ">=99.0"
+				err := renderer.Render(outputWriter, usedTheme, exampleCollection)
+				Expect(err).NotTo(BeNil())
+				Expect(err.Error()).To(Equal("Theme hard_coded requires postmanerator >=99.0, current version is 1.4.2"))
+				Expect(outputWriter.String()).To(BeEmpty())
+			})
+
+			It("should fail when the version constraint is invalid", func() {
+				usedTheme.Manifest.Postmanerator = "=>1.0"
+				err := renderer.Render(outputWriter, usedTheme, exampleCollection)
+				Expect(err).NotTo(BeNil())
+				Expect(err.Error()).To(Equal(`Invalid postmanerator constraint in theme hard_coded: unknown operator "=>" in "=>1.0"`))
+			})
+
+		})
+
+		It("should not check the version constraint of a development build", func() {
+			Expect(configuration.Version).To(Equal(configuration.DevVersion))
+			usedTheme.Manifest.Postmanerator = ">=99.0"
+			Expect(renderer.Render(outputWriter, usedTheme, exampleCollection)).To(BeNil())
+		})
+
+		It("should fail when a required helper is not available", func() {
+			usedTheme.Manifest.Helpers = []string{"markdown", "mermaid", "openapi"}
+			err := renderer.Render(outputWriter, usedTheme, exampleCollection)
+			Expect(err).NotTo(BeNil())
+			Expect(err.Error()).To(Equal("Theme hard_coded requires helpers that are not available: mermaid, openapi"))
+		})
+
+	})
+
+	Describe("Render with theme options", func() {
+
+		var (
+			outputWriter  *bytes.Buffer
+			usedTheme     *Theme
+			returnedError error
+		)
+
+		BeforeEach(func() {
+			renderer.Config = &configuration.Configuration{}
+			outputWriter = new(bytes.Buffer)
+			usedTheme = &Theme{
+				Name:  "options",
+				Files: []string{"tests_data/themes/options/index.tpl"},
+				Manifest: &Manifest{Options: map[string]OptionDefinition{
+					"company": {Default: "ACME"},
+					"color":   {Type: OptionString, Default: "blue", Values: []string{"blue", "green"}},
+					"columns": {Type: OptionInt, Default: 2},
+					"curl":    {Type: OptionBool},
+				}},
+			}
+		})
+
+		JustBeforeEach(func() {
+			returnedError = renderer.Render(outputWriter, usedTheme, exampleCollection)
+		})
+
+		It("should render the default values", func() {
+			Expect(returnedError).To(BeNil())
+			Expect(outputWriter.String()).To(Equal("My Collection by ACME, color blue, 2 columns\n"))
+		})
+
+		Context("when options are given", func() {
+
+			BeforeEach(func() {
+				renderer.Config.ThemeOptions.Set("company=Cats & Dogs, Inc.")
+				renderer.Config.ThemeOptions.Set("color=green")
+				renderer.Config.ThemeOptions.Set("columns=3")
+				renderer.Config.ThemeOptions.Set("curl=true")
+			})
+
+			It("should render their values", func() {
+				Expect(returnedError).To(BeNil())
+				Expect(outputWriter.String()).To(Equal("My Collection by Cats & Dogs, Inc., color green, 3 columns, with curl\n"))
+			})
+
+		})
+
+		Context("when an option is unknown", func() {
+
+			BeforeEach(func() {
+				renderer.Config.ThemeOptions.Set("logo=logo.png")
+			})
+
+			It("should return an error", func() {
+				Expect(returnedError).NotTo(BeNil())
+				Expect(returnedError.Error()).To(Equal("Unknown option logo for theme options"))
+			})
+
+		})
+
+		Context("when an option has an invalid value", func() {
+
+			BeforeEach(func() {
+				renderer.Config.ThemeOptions.Set("columns=many")
+			})
+
+			It("should return an error", func() {
+				Expect(returnedError).NotTo(BeNil())
+				Expect(returnedError.Error()).To(Equal(`Invalid value for theme option columns: expected an integer, got "many"`))
+			})
+
+		})
+
+		Context("when a string option with values has no default value", func() {
+
+			BeforeEach(func() {
+				usedTheme.Manifest.Options["color"] = OptionDefinition{Values: []string{"green", "blue"}}
+			})
+
+			It("should default to its first value", func() {
+				Expect(returnedError).To(BeNil())
+				Expect(outputWriter.String()).To(Equal("My Collection by ACME, color green, 2 columns\n"))
+			})
+
+		})
+
+		Context("when a string option has a value that is not allowed", func() {
+
+			BeforeEach(func() {
+				renderer.Config.ThemeOptions.Set("color=red")
+			})
+
+			It("should return an error", func() {
+				Expect(returnedError).NotTo(BeNil())
+				Expect(returnedError.Error()).To(Equal(`Invalid value for theme option color: expected one of blue, green, got "red"`))
+			})
+
+		})
+
+		Context("when an option is not given as key=value", func() {
+
+			BeforeEach(func() {
+				renderer.Config.ThemeOptions.Set("curl")
+			})
+
+			It("should return an error", func() {
+				Expect(returnedError).NotTo(BeNil())
+				Expect(returnedError.Error()).To(Equal("Invalid theme option curl, expected key=value"))
+			})
+
+		})
+
+	})
+
+	Describe("Render a theme extending another theme", func() {
+
+		var (
+			outputWriter *bytes.Buffer
+			parentTheme  *Theme
+			childTheme   *Theme
+		)
+
+		BeforeEach(func() {
+			outputWriter = new(bytes.Buffer)
+			parentTheme = &Theme{
+				Name:     "parent",
+				Files:    []string{"tests_data/themes/inheritance/parent/header.tpl", "tests_data/themes/inheritance/parent/index.tpl"},
+				Manifest: &Manifest{Options: map[string]OptionDefinition{"company": {Default: "ACME"}}},
+			}
+			childTheme = &Theme{
+				Name:     "child",
+				Files:    []string{"tests_data/themes/inheritance/child/custom.tpl"},
+				Manifest: &Manifest{Extends: "parent"},
+				Parent:   parentTheme,
+			}
+		})
+
+		It("should render the parent theme", func() {
+			Expect(renderer.Render(outputWriter, parentTheme, exampleCollection)).To(BeNil())
+			Expect(outputWriter.String()).To(Equal("PARENT HEADER\nBODY My Collection\n"))
+		})
+
+		It("should override the templates of the parent theme", func() {
+			Expect(renderer.Render(outputWriter, childTheme, exampleCollection)).To(BeNil())
+			Expect(outputWriter.String()).To(Equal("CHILD HEADER for ACME\nBODY My Collection\n"))
+		})
+
+		It("should check the requirements of the parent theme", func() {
+			parentTheme.Manifest.Helpers = []string{"mermaid"}
+			err := renderer.Render(outputWriter, childTheme, exampleCollection)
+			Expect(err).NotTo(BeNil())
+			Expect(err.Error()).To(Equal("Theme parent requires helpers that are not available: mermaid"))
+		})
+
+	})
+
+	Describe("RenderPages", func() {
+
+		var (
+			outputDirectory string
+			usedTheme       *Theme
+			returnedError   error
+		)
+
+		BeforeEach(func() {
+			outputDirectory = path.Join(os.TempDir(), uuid.NewV4().String())
+			usedTheme = &Theme{Files: []string{
+				"tests_data/themes/multi_page/folder.tpl",
+				"tests_data/themes/multi_page/index.tpl",
+				"tests_data/themes/multi_page/request.tpl",
+			}}
+		})
+
+		JustBeforeEach(func() {
+			returnedError = renderer.RenderPages(DirectoryOutput{Path: outputDirectory}, usedTheme, exampleCollection)
+		})
+
+		AfterEach(func() {
+			must(os.RemoveAll(outputDirectory))
+		})
+
+		It("should not return an error", func() {
+			Expect(returnedError).To(BeNil())
+		})
+
+		It("should generate the index page with links to its children", func() {
+			Expect(readFileContent(path.Join(outputDirectory, "index.html"))).To(Equal(`INDEX My Collection
+Get all cats -> get-all-cats.html
+Everything about dogs -> everything-about-dogs/index.html
+
+`))
+		})
+
+		It("should generate one page per folder", func() {
+			Expect(readFileContent(path.Join(outputDirectory, "everything-about-dogs", "index.html"))).To(Equal(`FOLDER Everything about dogs
+Up: ../index.html
+Get one dog by id -> get-one-dog-by-id.html
+Create a new dog -> create-a-new-dog.html
+Create a new dog with urlencoded values -> create-a-new-dog-with-urlencoded-values.html
+Create a new dog with form values -> create-a-new-dog-with-form-values.html
+
+`))
+		})
+
+		It("should generate one page per request", func() {
+			Expect(readFileContent(path.Join(outputDirectory, "get-all-cats.html"))).To(Equal(`REQUEST GET https://my-api/cats
+My Collection -> index.html
+
+`))
+			Expect(readFileContent(path.Join(outputDirectory, "everything-about-dogs", "get-one-dog-by-id.html"))).To(Equal(`REQUEST GET https://my-api/dogs/:id
+My Collection -> ../index.html
+Everything about dogs -> index.html
+
+`))
+		})
+
+		Context("when the theme has assets", func() {
+
+			BeforeEach(func() {
+				usedTheme = &Theme{
+					Path:   "tests_data/themes/assets",
+					Files:  []string{"tests_data/themes/assets/index.tpl"},
+					Assets: []string{"css/style.css"},
+				}
+			})
+
+			It("should copy the assets in the output directory", func() {
+				Expect(readFileContent(path.Join(outputDirectory, "assets", "css", "style.css"))).To(Equal("body { color: #333; }\n"))
+			})
+
+			It("should resolve the output path of the assets", func() {
+				Expect(readFileContent(path.Join(outputDirectory, "index.html"))).To(Equal(`<link rel="stylesheet" href="assets/css/style.css">` + "\n"))
+			})
+
+			Context("and the assets must be fingerprinted", func() {
+
+				BeforeEach(func() {
+					renderer.Config = &configuration.Configuration{FingerprintAssets: true}
+				})
+
+				It("should add the content hash to the names of the copied assets", func() {
+					Expect(path.Join(outputDirectory, "assets", "css", "style.97e2e949.css")).To(BeAnExistingFile())
+					Expect(path.Join(outputDirectory, "assets", "css", "style.css")).NotTo(BeAnExistingFile())
+				})
+
+				It("should resolve the fingerprinted output path of the assets", func() {
+					Expect(readFileContent(path.Join(outputDirectory, "index.html"))).To(Equal(`<link rel="stylesheet" href="assets/css/style.97e2e949.css">` + "\n"))
+				})
+
+			})
+
+			Context("and a template uses an asset that does not exist", func() {
+
+				BeforeEach(func() {
+					usedTheme.Assets = nil
+				})
+
+				It("should return an error", func() {
+					Expect(returnedError).NotTo(BeNil())
+					Expect(returnedError.Error()).To(ContainSubstring("Asset css/style.css not found in the theme"))
+				})
+
+			})
+
+		})
+
+		Context("when a request of a folder is named like the folder page", func() {
+
+			JustBeforeEach(func() {
+				collection := postman.Collection{Name: "Pets", Folders: []postman.Folder{{
+					Name:     "Dogs",
+					Requests: []postman.Request{{Name: "Index", Method: "GET", URL: "https://my-api/dogs"}},
+				}}}
+				returnedError = renderer.RenderPages(DirectoryOutput{Path: outputDirectory}, usedTheme, collection)
+			})
+
+			It("should not overwrite the folder page", func() {
+				Expect(returnedError).To(BeNil())
+				Expect(readFileContent(path.Join(outputDirectory, "dogs", "index.html"))).To(HavePrefix("FOLDER Dogs\n"))
+				Expect(readFileContent(path.Join(outputDirectory, "dogs", "index-2.html"))).To(HavePrefix("REQUEST GET https://my-api/dogs\n"))
+			})
+
+		})
+
+		Context("when a template looks up requests from a page", func() {
+
+			JustBeforeEach(func() {
+				usedTheme = &Theme{Files: []string{"tests_data/themes/lifecycle/index.tpl"}}
+				returnedError = renderer.RenderPages(DirectoryOutput{Path: outputDirectory}, usedTheme, lifecycleCollection)
+			})
+
+			It("should find the requests in the collection of the page", func() {
+				Expect(returnedError).To(BeNil())
+				Expect(readFileContent(path.Join(outputDirectory, "index.html"))).To(Equal(readFileContent("tests_data/themes/lifecycle.out")))
+			})
+
+		})
+
+		Context("when responses are truncated or binary", func() {
+
+			JustBeforeEach(func() {
+				usedTheme = &Theme{Files: []string{"tests_data/themes/bodies/index.tpl"}}
+				returnedError = renderer.RenderPages(DirectoryOutput{Path: outputDirectory}, usedTheme, bodiesCollection)
+			})
+
+			It("should write their complete bodies", func() {
+				Expect(readFileContent(path.Join(outputDirectory, "bodies", "get-all-cats-cats.json"))).To(Equal(`[{"name": "Felix"}, {"name": "Tom"}]`))
+				Expect(readFileContent(path.Join(outputDirectory, "bodies", "get-all-cats-photo.png"))).To(Equal("\x89PNG\r\n\x1a\n"))
+			})
+
+			It("should link to the body files", func() {
+				Expect(readFileContent(path.Join(outputDirectory, "index.html"))).To(Equal(`Cats: [{"name":"Felix"},"… 1 more item(s)"] -> bodies/get-all-cats-cats.json
+Photo: iVBORw0KGgo= -> bodies/get-all-cats-photo.png <img src="data:image/png;base64,iVBORw0KGgo=">
+
+`))
+			})
+
+		})
+
+		Context("when the theme extends a theme with assets", func() {
+
+			BeforeEach(func() {
+				usedTheme = &Theme{
+					Path:   "tests_data/themes/inheritance/child",
+					Files:  []string{"tests_data/themes/inheritance/child/custom.tpl"},
+					Assets: []string{"css/style.css"},
+					Parent: &Theme{
+						Path:   "tests_data/themes/inheritance/parent",
+						Files:  []string{"tests_data/themes/inheritance/parent/header.tpl", "tests_data/themes/inheritance/parent/index.tpl"},
+						Assets: []string{"css/style.css", "img/logo.svg"},
+					},
+				}
+			})
+
+			It("should copy the assets of both themes, the ones of the child theme taking precedence", func() {
+				Expect(returnedError).To(BeNil())
+				Expect(readFileContent(path.Join(outputDirectory, "assets", "css", "style.css"))).To(Equal("body { color: #c00; }\n"))
+				Expect(readFileContent(path.Join(outputDirectory, "assets", "img", "logo.svg"))).To(Equal("logo\n"))
+			})
+
+		})
+
+		Context("when the theme has no page templates", func() {
+
+			BeforeEach(func() {
+				usedTheme = &Theme{Files: []string{"tests_data/themes/hard_coded/index.tpl"}}
+			})
+
+			It("should only generate the index page", func() {
+				Expect(readFileContent(path.Join(outputDirectory, "index.html"))).To(Equal(readFileContent("tests_data/themes/hard_coded.out")))
+				Expect(path.Join(outputDirectory, "get-all-cats.html")).NotTo(BeAnExistingFile())
+				Expect(path.Join(outputDirectory, "everything-about-dogs")).NotTo(BeADirectory())
+			})
+
+		})
+
 	})
 
 })
@@ -0,0 +1 @@
+<link rel="stylesheet" href="{{ relativeURL .Page (asset "css/style.css") }}">
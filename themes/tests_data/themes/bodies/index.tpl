@@ -0,0 +1,2 @@
+{{ range .Requests }}{{ range .Responses }}{{ .Name }}: {{ .Body }}{{ with bodyFile . }} -> {{ . }}{{ end }}{{ if .IsImage }} <img src="{{ dataURI . }}">{{ end }}
+{{ end }}{{ end }}
@@ -0,0 +1,2 @@
+{{ define "header" }}CHILD HEADER for {{ .Options.company }}
+{{ end }}
\ No newline at end of file
@@ -0,0 +1,2 @@
+{{ define "header" }}PARENT HEADER
+{{ end }}
\ No newline at end of file
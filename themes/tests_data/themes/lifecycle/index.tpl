@@ -0,0 +1,4 @@
+{{ range .Requests }}{{ .Name }}
+{{ if .Lifecycle.Deprecated }}{{ if pastSunset . }}Removed{{ else }}Deprecated{{ end }}{{ with .Lifecycle.Sunset }}, sunset on {{ .Format "2006-01-02" }}{{ end }}{{ with findRequest $ .Lifecycle.ReplacedBy }}, use {{ .Method }} {{ .URL }} instead{{ end }}
+{{ end }}{{ with .Lifecycle.Since }}Since {{ . }}
+{{ end }}{{ end }}
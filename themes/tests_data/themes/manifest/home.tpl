@@ -0,0 +1 @@
+HOME {{ .Name }}
@@ -0,0 +1,4 @@
+FOLDER {{ .Page.Folder.Name }}
+Up: {{ relativeURL .Page .Page.Parent }}
+{{ range .Page.Children }}{{ .Title }} -> {{ relativeURL $.Page . }}
+{{ end }}
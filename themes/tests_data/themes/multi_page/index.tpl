@@ -0,0 +1,3 @@
+INDEX {{ .Name }}
+{{ range .Page.Children }}{{ .Title }} -> {{ relativeURL $.Page . }}
+{{ end }}
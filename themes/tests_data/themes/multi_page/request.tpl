@@ -0,0 +1,3 @@
+REQUEST {{ .Page.Request.Method }} {{ .Page.Request.URL }}
+{{ range .Page.Breadcrumbs }}{{ .Title }} -> {{ relativeURL $.Page . }}
+{{ end }}
@@ -0,0 +1 @@
+{{ .Name }} by {{ .Options.company }}, color {{ .Options.color }}, {{ .Options.columns }} columns{{ if .Options.curl }}, with curl{{ end }}
@@ -1,7 +1,60 @@
 package themes
 
+import "sort"
+
 type Theme struct {
-	Name  string
-	Path  string
-	Files []string
+	Name   string
+	Path   string
+	Files  []string
+	Assets []string
+	// Manifest is read from the theme.json or the theme.yaml file of the theme, it is nil when there is none.
+	Manifest *Manifest
+	// Parent is the theme this theme extends, see Manifest.Extends.
+	Parent *Theme
+}
+
+// templateFiles returns the templates of the parent themes, then the ones of the theme, so that the templates of the
+// theme override the ones of its parents.
+func (t *Theme) templateFiles() []string {
+	if t.Parent == nil {
+		return t.Files
+	}
+	return append(append([]string{}, t.Parent.templateFiles()...), t.Files...)
+}
+
+// assetSources maps the assets of the theme and of its parents to the directory of the theme they come from, the
+// assets of the theme taking precedence.
+func (t *Theme) assetSources() map[string]string {
+	sources := make(map[string]string)
+	if t.Parent != nil {
+		sources = t.Parent.assetSources()
+	}
+	for _, asset := range t.Assets {
+		sources[asset] = t.Path
+	}
+	return sources
+}
+
+// allAssets returns the sorted assets of the theme and of its parents.
+func (t *Theme) allAssets() []string {
+	assets := make([]string, 0)
+	for asset := range t.assetSources() {
+		assets = append(assets, asset)
+	}
+	sort.Strings(assets)
+	return assets
+}
+
+// optionDefinitions returns the options declared by the theme and by its parents.
+func (t *Theme) optionDefinitions() map[string]OptionDefinition {
+	definitions := make(map[string]OptionDefinition)
+	if t.Parent != nil {
+		definitions = t.Parent.optionDefinitions()
+	}
+	if t.Manifest != nil {
+		for name, definition := range t.Manifest.Options {
+			definitions[name] = definition
+		}
+	}
+	return definitions
 }
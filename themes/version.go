@@ -0,0 +1,90 @@
+package themes
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// semver is a major, minor and patch version, pre-release and build suffixes being ignored.
+type semver [3]int
+
+// parseVersion parses versions such as "1.2.3", "v1.2" or "1", missing parts being zero. It also returns the number
+// of parts that were given.
+func parseVersion(version string) (semver, int, error) {
+	var v semver
+	version = strings.TrimPrefix(strings.TrimSpace(version), "v")
+	if i := strings.IndexAny(version, "-+"); i >= 0 {
+		version = version[:i]
+	}
+	parts := strings.Split(version, ".")
+	if version == "" || len(parts) > 3 {
+		return v, 0, fmt.Errorf("invalid version %q", version)
+	}
+	for i, part := range parts {
+		n, err := strconv.Atoi(part)
+		if err != nil || n < 0 {
+			return v, 0, fmt.Errorf("invalid version %q", version)
+		}
+		v[i] = n
+	}
+	return v, len(parts), nil
+}
+
+func (v semver) compare(other semver) int {
+	for i := range v {
+		if v[i] != other[i] {
+			if v[i] < other[i] {
+				return -1
+			}
+			return 1
+		}
+	}
+	return 0
+}
+
+// satisfiesConstraint tells whether a version satisfies all the comparisons of a constraint, separated by spaces or
+// commas, such as ">=1.2.0 <2". The supported operators are =, !=, >, >=, <, <=, ^ (same major version) and
+// ~ (same minor version). A version without operator must match exactly.
+func satisfiesConstraint(version, constraint string) (bool, error) {
+	v, _, err := parseVersion(version)
+	if err != nil {
+		return false, err
+	}
+	comparisons := strings.Fields(strings.Replace(constraint, ",", " ", -1))
+	if len(comparisons) == 0 {
+		return false, fmt.Errorf("empty constraint")
+	}
+	for _, comparison := range comparisons {
+		operator := comparison[:len(comparison)-len(strings.TrimLeft(comparison, "<>=!^~"))]
+		target, parts, err := parseVersion(comparison[len(operator):])
+		if err != nil {
+			return false, err
+		}
+		ok := false
+		switch c := v.compare(target); operator {
+		case "", "=", "==":
+			ok = c == 0
+		case "!=":
+			ok = c != 0
+		case ">":
+			ok = c > 0
+		case ">=":
+			ok = c >= 0
+		case "<":
+			ok = c < 0
+		case "<=":
+			ok = c <= 0
+		case "^":
+			ok = c >= 0 && v[0] == target[0]
+		case "~":
+			ok = c >= 0 && v[0] == target[0] && (parts == 1 || v[1] == target[1])
+		default:
+			return false, fmt.Errorf("unknown operator %q in %q", operator, comparison)
+		}
+		if !ok {
+			return false, nil
+		}
+	}
+	return true, nil
+}
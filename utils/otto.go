@@ -0,0 +1,32 @@
+package utils
+
+import (
+	"errors"
+	"time"
+
+	"github.com/robertkrimen/otto"
+)
+
+var ErrScriptTimeout = errors.New("script timed out")
+
+// RunScript runs some Javascript in the given VM, and interrupts it if it runs for longer than the timeout.
+func RunScript(vm *otto.Otto, src string, timeout time.Duration) (value otto.Value, err error) {
+	interrupt := make(chan func(), 1)
+	vm.Interrupt = interrupt
+	timer := time.AfterFunc(timeout, func() {
+		interrupt <- func() {
+			panic(ErrScriptTimeout)
+		}
+	})
+	defer func() {
+		timer.Stop()
+		vm.Interrupt = nil
+		if caught := recover(); caught != nil {
+			if caught != ErrScriptTimeout {
+				panic(caught)
+			}
+			err = ErrScriptTimeout
+		}
+	}()
+	return vm.Run(src)
+}
@@ -0,0 +1,45 @@
+package utils_test
+
+import (
+	"time"
+
+	. "github.com/aubm/postmanerator/utils"
+	"github.com/robertkrimen/otto"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("RunScript", func() {
+
+	var vm *otto.Otto
+
+	BeforeEach(func() {
+		vm = otto.New()
+	})
+
+	It("should run the script", func() {
+		value, err := RunScript(vm, "1 + 2", time.Second)
+		Expect(err).To(BeNil())
+		Expect(value.String()).To(Equal("3"))
+	})
+
+	It("should return the script errors", func() {
+		_, err := RunScript(vm, "undefinedFunction()", time.Second)
+		Expect(err).NotTo(BeNil())
+		Expect(err.Error()).To(ContainSubstring("ReferenceError"))
+	})
+
+	It("should interrupt the scripts running for too long", func() {
+		_, err := RunScript(vm, "while (true) {}", 50*time.Millisecond)
+		Expect(err).To(Equal(ErrScriptTimeout))
+	})
+
+	It("should leave the VM usable after a timeout", func() {
+		RunScript(vm, "while (true) {}", 50*time.Millisecond)
+		value, err := RunScript(vm, "'still alive'", time.Second)
+		Expect(err).To(BeNil())
+		Expect(value.String()).To(Equal("still alive"))
+	})
+
+})
@@ -0,0 +1,139 @@
+package verify
+
+import (
+	"encoding/json"
+	"fmt"
+	"mime"
+	"net/http"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/aubm/postmanerator/postman"
+)
+
+// skippedHeaders depend on the transport rather than on the API.
+var skippedHeaders = map[string]bool{
+	"content-length":    true,
+	"transfer-encoding": true,
+	"connection":        true,
+}
+
+func compareStatusCode(expected postman.Response, actual *http.Response) []string {
+	if expected.StatusCode == 0 || expected.StatusCode == actual.StatusCode {
+		return nil
+	}
+	return []string{fmt.Sprintf("expected status code %v, got %v", expected.StatusCode, actual.StatusCode)}
+}
+
+func compareHeaders(expected postman.Response, actual *http.Response, exact bool) []string {
+	failures := make([]string, 0)
+	for _, header := range expected.Headers {
+		if skippedHeaders[strings.ToLower(header.Key)] {
+			continue
+		}
+		expectedValue := fmt.Sprint(header.Value)
+		actualValue, ok := actual.Header[http.CanonicalHeaderKey(header.Key)]
+		switch {
+		case !ok:
+			failures = append(failures, fmt.Sprintf("expected header %v, got none", header.Key))
+		case exact && strings.Join(actualValue, ", ") != expectedValue:
+			failures = append(failures, fmt.Sprintf("expected header %v to be %q, got %q", header.Key, expectedValue, strings.Join(actualValue, ", ")))
+		case !exact && strings.EqualFold(header.Key, "Content-Type") && !sameMediaType(expectedValue, actualValue[0]):
+			failures = append(failures, fmt.Sprintf("expected header %v to be %q, got %q", header.Key, expectedValue, actualValue[0]))
+		}
+	}
+	return failures
+}
+
+func sameMediaType(a, b string) bool {
+	mediaTypeA, _, errA := mime.ParseMediaType(a)
+	mediaTypeB, _, errB := mime.ParseMediaType(b)
+	return errA == nil && errB == nil && mediaTypeA == mediaTypeB
+}
+
+// compareBodies compares JSON bodies by shape, or by value in exact mode.
+// Other bodies are only compared in exact mode.
+func compareBodies(expected, actual string, exact bool) []string {
+	var expectedJSON, actualJSON interface{}
+	if strings.TrimSpace(expected) == "" || json.Unmarshal([]byte(expected), &expectedJSON) != nil {
+		if exact && strings.TrimSpace(expected) != strings.TrimSpace(actual) {
+			return []string{"expected the body to be identical to the saved response body"}
+		}
+		return nil
+	}
+
+	if err := json.Unmarshal([]byte(actual), &actualJSON); err != nil {
+		return []string{fmt.Sprintf("expected a JSON body, got %q", truncate(actual))}
+	}
+
+	if exact {
+		if !reflect.DeepEqual(expectedJSON, actualJSON) {
+			return []string{"expected the JSON body to be identical to the saved response body"}
+		}
+		return nil
+	}
+	return compareShapes("$", expectedJSON, actualJSON)
+}
+
+func compareShapes(path string, expected, actual interface{}) []string {
+	if expected == nil {
+		return nil
+	}
+	if kindOf(expected) != kindOf(actual) {
+		return []string{fmt.Sprintf("expected %v at %v, got %v", kindOf(expected), path, kindOf(actual))}
+	}
+
+	failures := make([]string, 0)
+	switch expectedValue := expected.(type) {
+	case map[string]interface{}:
+		actualValue := actual.(map[string]interface{})
+		keys := make([]string, 0, len(expectedValue))
+		for key := range expectedValue {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			value, ok := actualValue[key]
+			if !ok {
+				failures = append(failures, fmt.Sprintf("expected property %v.%v, got none", path, key))
+				continue
+			}
+			failures = append(failures, compareShapes(path+"."+key, expectedValue[key], value)...)
+		}
+	case []interface{}:
+		if len(expectedValue) == 0 {
+			return nil
+		}
+		for i, value := range actual.([]interface{}) {
+			failures = append(failures, compareShapes(fmt.Sprintf("%v[%d]", path, i), expectedValue[0], value)...)
+		}
+	}
+	return failures
+}
+
+func kindOf(value interface{}) string {
+	switch value.(type) {
+	case map[string]interface{}:
+		return "object"
+	case []interface{}:
+		return "array"
+	case string:
+		return "string"
+	case float64:
+		return "number"
+	case bool:
+		return "boolean"
+	case nil:
+		return "null"
+	}
+	return "unknown"
+}
+
+func truncate(s string) string {
+	const max = 100
+	if len(s) <= max {
+		return s
+	}
+	return s[:max] + "..."
+}
@@ -0,0 +1,151 @@
+package verify
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/fatih/color"
+)
+
+type Report struct {
+	Name  string
+	Cases []Case
+}
+
+// Case is the check of one saved response against the live response.
+type Case struct {
+	Suite    string
+	Name     string
+	Duration time.Duration
+	Skipped  bool
+	Failures []string
+}
+
+func (c Case) Failed() bool {
+	return len(c.Failures) > 0
+}
+
+func (r Report) Failed() int {
+	failed := 0
+	for _, c := range r.Cases {
+		if c.Failed() {
+			failed++
+		}
+	}
+	return failed
+}
+
+func (r Report) Skipped() int {
+	skipped := 0
+	for _, c := range r.Cases {
+		if c.Skipped {
+			skipped++
+		}
+	}
+	return skipped
+}
+
+func (r Report) WriteText(w io.Writer) error {
+	for _, c := range r.Cases {
+		var err error
+		switch {
+		case c.Skipped:
+			_, err = fmt.Fprintf(w, "%v %v / %v (no saved response)\n", color.YellowString("SKIP"), c.Suite, c.Name)
+		case c.Failed():
+			_, err = fmt.Fprintf(w, "%v %v / %v\n", color.RedString("FAIL"), c.Suite, c.Name)
+			for _, failure := range c.Failures {
+				if err == nil {
+					_, err = fmt.Fprintf(w, "     %v\n", failure)
+				}
+			}
+		default:
+			_, err = fmt.Fprintf(w, "%v %v / %v\n", color.GreenString("PASS"), c.Suite, c.Name)
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	passed := len(r.Cases) - r.Failed() - r.Skipped()
+	_, err := fmt.Fprintf(w, "\n%d passed, %d failed, %d skipped\n", passed, r.Failed(), r.Skipped())
+	return err
+}
+
+type junitTestSuites struct {
+	XMLName  xml.Name         `xml:"testsuites"`
+	Tests    int              `xml:"tests,attr"`
+	Failures int              `xml:"failures,attr"`
+	Skipped  int              `xml:"skipped,attr"`
+	Suites   []junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name     string          `xml:"name,attr"`
+	Tests    int             `xml:"tests,attr"`
+	Failures int             `xml:"failures,attr"`
+	Skipped  int             `xml:"skipped,attr"`
+	Time     string          `xml:"time,attr"`
+	Cases    []junitTestCase `xml:"testcase"`
+}
+
+type junitTestCase struct {
+	ClassName string        `xml:"classname,attr"`
+	Name      string        `xml:"name,attr"`
+	Time      string        `xml:"time,attr"`
+	Failure   *junitFailure `xml:"failure,omitempty"`
+	Skipped   *struct{}     `xml:"skipped,omitempty"`
+}
+
+type junitFailure struct {
+	Message string `xml:"message,attr"`
+	Details string `xml:",chardata"`
+}
+
+func (r Report) WriteJUnit(w io.Writer) error {
+	suite := junitTestSuite{
+		Name:     r.Name,
+		Tests:    len(r.Cases),
+		Failures: r.Failed(),
+		Skipped:  r.Skipped(),
+		Cases:    make([]junitTestCase, 0, len(r.Cases)),
+	}
+
+	var total time.Duration
+	for _, c := range r.Cases {
+		total += c.Duration
+		testCase := junitTestCase{ClassName: c.Suite, Name: c.Name, Time: junitTime(c.Duration)}
+		if c.Skipped {
+			testCase.Skipped = &struct{}{}
+		}
+		if c.Failed() {
+			testCase.Failure = &junitFailure{Message: c.Failures[0], Details: strings.Join(c.Failures, "\n")}
+		}
+		suite.Cases = append(suite.Cases, testCase)
+	}
+	suite.Time = junitTime(total)
+
+	suites := junitTestSuites{
+		Tests:    suite.Tests,
+		Failures: suite.Failures,
+		Skipped:  suite.Skipped,
+		Suites:   []junitTestSuite{suite},
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("", "  ")
+	if err := encoder.Encode(suites); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
+
+func junitTime(d time.Duration) string {
+	return fmt.Sprintf("%.3f", d.Seconds())
+}
@@ -0,0 +1,188 @@
+package verify
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/aubm/postmanerator/postman"
+)
+
+const defaultTimeout = 30 * time.Second
+
+// Runner replays the requests of a collection and checks the live responses against the saved ones.
+type Runner struct {
+	// BaseURL replaces the scheme and the host of the collection URLs, if not empty.
+	BaseURL string
+	// Exact requires header values and bodies to be identical, instead of only having the same shape.
+	Exact  bool
+	Client *http.Client
+}
+
+// Run sends one request for each saved response of the collection, using its original request when available.
+func (r *Runner) Run(collection postman.Collection) Report {
+	report := Report{Name: collection.Name, Cases: make([]Case, 0)}
+	folder := postman.Folder{Name: collection.Name, Requests: collection.Requests, Folders: collection.Folders}
+	r.runFolder(&report, collection.Name, folder)
+	return report
+}
+
+func (r *Runner) runFolder(report *Report, suite string, folder postman.Folder) {
+	for _, request := range folder.Requests {
+		if len(request.Responses) == 0 {
+			report.Cases = append(report.Cases, Case{Suite: suite, Name: request.Name, Skipped: true})
+			continue
+		}
+		for _, response := range request.Responses {
+			report.Cases = append(report.Cases, r.runCase(suite, request, response))
+		}
+	}
+	for _, subFolder := range folder.Folders {
+		r.runFolder(report, suite+"/"+subFolder.Name, subFolder)
+	}
+}
+
+func (r *Runner) runCase(suite string, request postman.Request, response postman.Response) Case {
+	c := Case{Suite: suite, Name: fmt.Sprintf("%v - %v", request.Name, response.Name)}
+
+	sentRequest := request
+	if response.OriginalRequest != nil {
+		sentRequest = *response.OriginalRequest
+		if len(sentRequest.PathVariables) == 0 {
+			sentRequest.PathVariables = request.PathVariables
+		}
+	}
+
+	start := time.Now()
+	liveResponse, body, err := r.send(sentRequest)
+	c.Duration = time.Since(start)
+	if err != nil {
+		c.Failures = append(c.Failures, err.Error())
+		return c
+	}
+
+	c.Failures = append(c.Failures, compareStatusCode(response, liveResponse)...)
+	c.Failures = append(c.Failures, compareHeaders(response, liveResponse, r.Exact)...)
+	c.Failures = append(c.Failures, compareBodies(response.CompleteBody(), string(body), r.Exact)...)
+	return c
+}
+
+func (r *Runner) send(request postman.Request) (*http.Response, []byte, error) {
+	requestURL, err := r.resolveURL(request)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	body, contentType, err := requestBody(request)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to build request body: %v", err)
+	}
+
+	httpRequest, err := http.NewRequest(request.Method, requestURL, body)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to build request: %v", err)
+	}
+	for _, header := range request.Headers {
+		httpRequest.Header.Add(header.Key, fmt.Sprint(header.Value))
+	}
+	if contentType != "" {
+		httpRequest.Header.Set("Content-Type", contentType)
+	}
+
+	client := r.Client
+	if client == nil {
+		client = &http.Client{Timeout: defaultTimeout}
+	}
+	res, err := client.Do(httpRequest)
+	if err != nil {
+		return nil, nil, fmt.Errorf("request failed: %v", err)
+	}
+	defer res.Body.Close()
+
+	resBody, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read response body: %v", err)
+	}
+	return res, resBody, nil
+}
+
+func (r *Runner) resolveURL(request postman.Request) (string, error) {
+	request.URL = replacePathVariables(request.URL, request.PathVariables)
+	rawURL := request.URL
+	if r.BaseURL != "" {
+		rawURL = strings.TrimSuffix(r.BaseURL, "/") + request.RequestURI()
+	}
+
+	parsedURL, err := url.Parse(rawURL)
+	if err != nil {
+		return "", fmt.Errorf("invalid URL %v: %v", rawURL, err)
+	}
+	if !parsedURL.IsAbs() {
+		return "", fmt.Errorf("URL %v is not absolute, please provide a base URL", rawURL)
+	}
+	return parsedURL.String(), nil
+}
+
+func replacePathVariables(rawURL string, variables []postman.KeyValuePair) string {
+	for _, variable := range variables {
+		value := url.PathEscape(fmt.Sprint(variable.Value))
+		rawURL = replaceSegment(rawURL, ":"+variable.Key, value)
+	}
+	return rawURL
+}
+
+// replaceSegment replaces a whole path segment, so that ":id" does not replace the beginning of ":identifier".
+func replaceSegment(rawURL, segment, value string) string {
+	for i := 0; i < len(rawURL); {
+		j := strings.Index(rawURL[i:], segment)
+		if j < 0 {
+			break
+		}
+		j += i
+		end := j + len(segment)
+		if end == len(rawURL) || strings.ContainsRune("/?#", rune(rawURL[end])) {
+			rawURL = rawURL[:j] + value + rawURL[end:]
+			end = j + len(value)
+		}
+		i = end
+	}
+	return rawURL
+}
+
+func requestBody(request postman.Request) (io.Reader, string, error) {
+	switch request.PayloadType {
+	case "raw":
+		if request.PayloadRaw == "" {
+			return nil, "", nil
+		}
+		return strings.NewReader(request.PayloadRaw), "", nil
+	case "urlencoded":
+		values := url.Values{}
+		for _, param := range request.PayloadParams {
+			values.Add(param.Key, fmt.Sprint(param.Value))
+		}
+		return strings.NewReader(values.Encode()), "application/x-www-form-urlencoded", nil
+	case "formdata", "params":
+		if len(request.PayloadParams) == 0 {
+			return nil, "", nil
+		}
+		body := new(bytes.Buffer)
+		writer := multipart.NewWriter(body)
+		for _, param := range request.PayloadParams {
+			if err := writer.WriteField(param.Key, fmt.Sprint(param.Value)); err != nil {
+				return nil, "", err
+			}
+		}
+		if err := writer.Close(); err != nil {
+			return nil, "", err
+		}
+		return body, writer.FormDataContentType(), nil
+	}
+	return nil, "", nil
+}
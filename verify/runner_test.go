@@ -0,0 +1,272 @@
+package verify_test
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"time"
+
+	"github.com/aubm/postmanerator/postman"
+	. "github.com/aubm/postmanerator/verify"
+	"github.com/fatih/color"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Runner", func() {
+
+	var (
+		server     *httptest.Server
+		handler    http.HandlerFunc
+		collection postman.Collection
+		runner     *Runner
+		report     Report
+	)
+
+	BeforeEach(func() {
+		handler = func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json; charset=utf-8")
+			fmt.Fprint(w, `{"name":"Barney","age":3,"toys":[{"name":"ball"}]}`)
+		}
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			handler(w, r)
+		}))
+		collection = postman.Collection{
+			Name: "Cats API",
+			Requests: []postman.Request{{
+				Name:   "Get one cat",
+				Method: "GET",
+				URL:    "{{url}}/cats/:id",
+				PathVariables: []postman.KeyValuePair{
+					{Key: "id", Value: "1"},
+				},
+				Responses: []postman.Response{{
+					Name:       "OK",
+					StatusCode: 200,
+					Headers:    []postman.KeyValuePair{{Key: "Content-Type", Value: "application/json"}},
+					Body:       `{"name":"Sam","age":5,"toys":[{"name":"mouse"}]}`,
+				}},
+			}},
+		}
+		runner = &Runner{BaseURL: server.URL}
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	JustBeforeEach(func() {
+		report = runner.Run(collection)
+	})
+
+	Context("when the live response has the same shape", func() {
+
+		var receivedPath string
+
+		BeforeEach(func() {
+			inner := handler
+			handler = func(w http.ResponseWriter, r *http.Request) {
+				receivedPath = r.URL.Path
+				inner(w, r)
+			}
+		})
+
+		It("should pass", func() {
+			Expect(report.Cases).To(HaveLen(1))
+			Expect(report.Cases[0].Suite).To(Equal("Cats API"))
+			Expect(report.Cases[0].Name).To(Equal("Get one cat - OK"))
+			Expect(report.Cases[0].Failures).To(BeEmpty())
+			Expect(report.Failed()).To(Equal(0))
+		})
+
+		It("should replace the host and the path variables", func() {
+			Expect(receivedPath).To(Equal("/cats/1"))
+		})
+
+	})
+
+	Context("when the exact mode is enabled", func() {
+
+		BeforeEach(func() {
+			runner.Exact = true
+		})
+
+		It("should fail on different values", func() {
+			Expect(report.Cases[0].Failures).To(Equal([]string{
+				`expected header Content-Type to be "application/json", got "application/json; charset=utf-8"`,
+				"expected the JSON body to be identical to the saved response body",
+			}))
+		})
+
+	})
+
+	Context("when the live response differs", func() {
+
+		BeforeEach(func() {
+			handler = func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "text/plain")
+				w.WriteHeader(500)
+				fmt.Fprint(w, `{"name":1,"toys":[{"name":"ball"},{}]}`)
+			}
+		})
+
+		It("should report every difference", func() {
+			Expect(report.Cases[0].Failures).To(Equal([]string{
+				"expected status code 200, got 500",
+				`expected header Content-Type to be "application/json", got "text/plain"`,
+				"expected property $.age, got none",
+				"expected string at $.name, got number",
+				"expected property $.toys[1].name, got none",
+			}))
+			Expect(report.Failed()).To(Equal(1))
+		})
+
+	})
+
+	Context("when a response has an original request", func() {
+
+		var (
+			receivedMethod string
+			receivedPath   string
+			receivedQuery  string
+			receivedHeader string
+			receivedBody   string
+		)
+
+		BeforeEach(func() {
+			handler = func(w http.ResponseWriter, r *http.Request) {
+				body, _ := ioutil.ReadAll(r.Body)
+				receivedMethod, receivedPath, receivedQuery = r.Method, r.URL.Path, r.URL.RawQuery
+				receivedHeader, receivedBody = r.Header.Get("X-Api-Key"), string(body)
+				w.WriteHeader(201)
+			}
+			collection.Requests = []postman.Request{{
+				Name:   "Create a cat",
+				Method: "POST",
+				URL:    "https://my-api.com/cats",
+				Responses: []postman.Response{{
+					Name:       "Created",
+					StatusCode: 201,
+					OriginalRequest: &postman.Request{
+						Method:      "POST",
+						URL:         "https://my-api.com/cats?notify=true",
+						Headers:     []postman.KeyValuePair{{Key: "X-Api-Key", Value: "secret"}},
+						PayloadType: "raw",
+						PayloadRaw:  `{"name":"Sam"}`,
+					},
+				}},
+			}}
+		})
+
+		It("should send the original request", func() {
+			Expect(report.Cases[0].Failures).To(BeEmpty())
+			Expect(receivedMethod).To(Equal("POST"))
+			Expect(receivedPath).To(Equal("/cats"))
+			Expect(receivedQuery).To(Equal("notify=true"))
+			Expect(receivedHeader).To(Equal("secret"))
+			Expect(receivedBody).To(Equal(`{"name":"Sam"}`))
+		})
+
+	})
+
+	Context("when a request has no saved response", func() {
+
+		BeforeEach(func() {
+			collection.Folders = []postman.Folder{{
+				Name:     "Dogs",
+				Requests: []postman.Request{{Name: "Get all dogs", Method: "GET", URL: "{{url}}/dogs"}},
+			}}
+		})
+
+		It("should skip it", func() {
+			Expect(report.Cases).To(HaveLen(2))
+			Expect(report.Cases[1]).To(Equal(Case{Suite: "Cats API/Dogs", Name: "Get all dogs", Skipped: true}))
+			Expect(report.Skipped()).To(Equal(1))
+		})
+
+	})
+
+	Context("when the server can not be reached", func() {
+
+		BeforeEach(func() {
+			runner.BaseURL = "http://127.0.0.1:1"
+		})
+
+		It("should fail", func() {
+			Expect(report.Cases[0].Failures).To(HaveLen(1))
+			Expect(report.Cases[0].Failures[0]).To(HavePrefix("request failed: "))
+		})
+
+	})
+
+	Context("when no base URL is provided and the URL is not absolute", func() {
+
+		BeforeEach(func() {
+			runner.BaseURL = ""
+		})
+
+		It("should fail", func() {
+			Expect(report.Cases[0].Failures).To(Equal([]string{
+				"URL {{url}}/cats/1 is not absolute, please provide a base URL",
+			}))
+		})
+
+	})
+
+})
+
+var _ = Describe("Report", func() {
+
+	var report Report
+
+	BeforeEach(func() {
+		report = Report{
+			Name: "Cats API",
+			Cases: []Case{
+				{Suite: "Cats API", Name: "Get all cats - OK", Duration: 12 * time.Millisecond},
+				{Suite: "Cats API/Dogs", Name: "Get one dog - OK", Duration: time.Second, Failures: []string{
+					"expected status code 200, got 500",
+					"expected property $.name, got none",
+				}},
+				{Suite: "Cats API/Dogs", Name: "Get all dogs", Skipped: true},
+			},
+		}
+	})
+
+	It("should write a text report", func() {
+		out := new(bytes.Buffer)
+		Expect(report.WriteText(out)).To(Succeed())
+		Expect(out.String()).To(Equal(strings.Join([]string{
+			color.GreenString("PASS") + " Cats API / Get all cats - OK",
+			color.RedString("FAIL") + " Cats API/Dogs / Get one dog - OK",
+			"     expected status code 200, got 500",
+			"     expected property $.name, got none",
+			color.YellowString("SKIP") + " Cats API/Dogs / Get all dogs (no saved response)",
+			"",
+			"1 passed, 1 failed, 1 skipped",
+			"",
+		}, "\n")))
+	})
+
+	It("should write a JUnit report", func() {
+		out := new(bytes.Buffer)
+		Expect(report.WriteJUnit(out)).To(Succeed())
+		Expect(out.String()).To(Equal(`<?xml version="1.0" encoding="UTF-8"?>
+<testsuites tests="3" failures="1" skipped="1">
+  <testsuite name="Cats API" tests="3" failures="1" skipped="1" time="1.012">
+    <testcase classname="Cats API" name="Get all cats - OK" time="0.012"></testcase>
+    <testcase classname="Cats API/Dogs" name="Get one dog - OK" time="1.000">
+      <failure message="expected status code 200, got 500">expected status code 200, got 500&#xA;expected property $.name, got none</failure>
+    </testcase>
+    <testcase classname="Cats API/Dogs" name="Get all dogs" time="0.000">
+      <skipped></skipped>
+    </testcase>
+  </testsuite>
+</testsuites>
+`))
+	})
+
+})